@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"log/slog"
@@ -11,15 +12,21 @@ import (
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		panic("missing arg: logfile path")
+	debug := flag.Bool("debug", false, "log debug messages, including the content of every message")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: server [-debug] logfile")
+		flag.PrintDefaults()
 	}
-	logPath := os.Args[1]
-	setupLogging(logPath, true)
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	setupLogging(flag.Arg(0), *debug)
 
 	slog.Info("Language Server started.")
 
-	srv := server.New(nil, nil, true) // TODO this should be a program arg
+	srv := server.New(nil, nil, *debug)
 	srv.ListenAndServe(os.Stdin, os.Stdout)
 }
 
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/analysis"
+	"github.com/scatternoodle/wflang/wflang/lint"
+)
+
+// runCheck checks the formula files at the given paths, which default to the
+// current directory, for syntax errors, type errors and lint diagnostics, with the
+// rules and catalogues configured by the project configuration. It exits 1 if any
+// file has a diagnostic at least as severe as the -fail-on threshold or cannot be
+// read, and 2 on bad usage.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	formats := analysis.Formats()
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: wflang check [-format %s] [-fail-on severity] [-j n] [paths...]\n", strings.Join(names, "|"))
+		flags.PrintDefaults()
+	}
+	format := flags.String("format", "text", "output `format`, one of "+strings.Join(names, ", "))
+	failOn := flags.String("fail-on", "error", "exit 1 on diagnostics at least as severe as `severity`: error, warning, info or hint")
+	workers := flags.Int("j", 0, "files checked in parallel, defaults to the number of CPUs")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	write, ok := formats[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "wflang check: unknown format %q\n", *format)
+		return 2
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil || threshold == lint.SeverityOff {
+		fmt.Fprintf(stderr, "wflang check: -fail-on: invalid severity %q\n", *failOn)
+		return 2
+	}
+
+	project, ok := projectConfig("check", stderr)
+	if !ok {
+		return 2
+	}
+	cat, err := project.Catalogs.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang check: %s\n", err)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	paths, err = findFiles(formulaExt, paths...)
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang check: %s\n", err)
+		return 2
+	}
+
+	files := analysis.CheckFiles(paths, analysis.Options{
+		Lint:    project.Lint,
+		Catalog: cat,
+		Schemas: project.Schemas(),
+		Workers: *workers,
+	})
+	if err := write(stdout, files, threshold); err != nil {
+		fmt.Fprintf(stderr, "wflang check: %s\n", err)
+		return 2
+	}
+	for _, f := range files {
+		if f.Failed(threshold) {
+			return 1
+		}
+	}
+	return 0
+}
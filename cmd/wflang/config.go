@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/scatternoodle/wflang/wflang/config"
+)
+
+// projectConfig loads the project configuration found from the current
+// directory, reporting any error on stderr as the command name.
+func projectConfig(name string, stderr io.Writer) (config.Config, bool) {
+	cfg, err := config.Discover(".")
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang %s: %s\n", name, err)
+		return config.Config{}, false
+	}
+	return cfg, true
+}
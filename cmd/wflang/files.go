@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// findFiles returns the files at paths. Directories are searched recursively for
+// files with the extension ext.
+func findFiles(ext string, paths ...string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (p == path || filepath.Ext(p) == ext) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
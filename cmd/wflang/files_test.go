@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.wflang", "b.wftest", "notes.txt", filepath.Join("sub", "c.wflang")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ext     string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{"directory", ".wflang", []string{dir}, []string{"a.wflang", filepath.Join("sub", "c.wflang")}, false},
+		{"other extension", ".wftest", []string{dir}, []string{"b.wftest"}, false},
+		{"named file", ".wflang", []string{filepath.Join(dir, "notes.txt")}, []string{"notes.txt"}, false},
+		{"several paths", ".wflang", []string{filepath.Join(dir, "sub"), filepath.Join(dir, "a.wflang")}, []string{filepath.Join("sub", "c.wflang"), "a.wflang"}, false},
+		{"missing", ".wflang", []string{filepath.Join(dir, "missing")}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := findFiles(tt.ext, tt.paths...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("have error %v, want error %t", err, tt.wantErr)
+			}
+			var want []string
+			for _, name := range tt.want {
+				want = append(want, filepath.Join(dir, name))
+			}
+			if !slices.Equal(have, want) {
+				t.Errorf("have %v, want %v", have, want)
+			}
+		})
+	}
+}
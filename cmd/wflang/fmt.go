@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/scatternoodle/wflang/wflang/format"
+)
+
+// formulaExt is the file extension of formula files.
+const formulaExt = ".wflang"
+
+// runFmt formats the formula files at the given paths, or stdin if there are none.
+// By default the formatted formulas are printed to stdout, formatted as the
+// project configuration says unless overridden by flags. It exits 1 if any file
+// cannot be formatted, and 2 on bad usage.
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: wflang fmt [-w | -d] [-indent n] [-width n] [paths...]")
+		flags.PrintDefaults()
+	}
+	project, ok := projectConfig("fmt", stderr)
+	if !ok {
+		return 2
+	}
+	opts := project.Format.Options()
+	write := flags.Bool("w", false, "write the result to the source file instead of stdout")
+	diff := flags.Bool("d", false, "print diffs instead of the formatted formulas")
+	flags.IntVar(&opts.Indent, "indent", opts.Indent, "spaces by which wrapped and/or chains are indented")
+	flags.IntVar(&opts.LineWidth, "width", opts.LineWidth, "line width beyond which lines are wrapped")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *write && *diff {
+		fmt.Fprintln(stderr, "wflang fmt: -w and -d are mutually exclusive")
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(stderr, "wflang fmt: cannot use -w with stdin")
+			return 2
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "wflang fmt: %s\n", err)
+			return 1
+		}
+		if err := fmtFile("<stdin>", string(src), opts, false, *diff, stdout); err != nil {
+			fmt.Fprintf(stderr, "wflang fmt: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	files, err := findFiles(formulaExt, flags.Args()...)
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang fmt: %s\n", err)
+		return 1
+	}
+	code := 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err == nil {
+			err = fmtFile(file, string(src), opts, *write, *diff, stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "wflang fmt: %s\n", err)
+			code = 1
+		}
+	}
+	return code
+}
+
+func fmtFile(path, src string, opts format.Options, write, diff bool, stdout io.Writer) error {
+	out, err := format.Source(src, opts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	switch {
+	case write:
+		if out == src {
+			return nil
+		}
+		return os.WriteFile(path, []byte(out), 0o644)
+	case diff:
+		fmt.Fprint(stdout, unifiedDiff(path, src, out))
+	default:
+		fmt.Fprint(stdout, out)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/lint"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+// runLint lints the formula files at the given paths, which default to the
+// current directory, with the rules configured by the project configuration and
+// the -rule flags. It exits 1 if anything is reported or a file cannot be
+// linted, and 2 on bad usage.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: wflang lint [-list] [-rule id=severity]... [paths...]")
+		flags.PrintDefaults()
+	}
+	list := flags.Bool("list", false, "list the lint rules and exit")
+	cfg := lint.Config{}
+	flags.Func("rule", "set the severity of the rule `id=severity`, one of error, warning, info, hint or off", func(s string) error {
+		id, sev, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("have %q, want id=severity", s)
+		}
+		severity, err := lint.ParseSeverity(sev)
+		if err != nil {
+			return err
+		}
+		rc := cfg[id]
+		rc.Severity = severity
+		cfg[id] = rc
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "wflang lint: %s\n", err)
+		return 2
+	}
+	project, ok := projectConfig("lint", stderr)
+	if !ok {
+		return 2
+	}
+	cfg = withSeverities(project.Lint, cfg)
+
+	if *list {
+		for _, rule := range lint.Rules() {
+			fmt.Fprintf(stdout, "%-18s %-8s %s\n", rule.ID(), rule.Severity(), rule.Doc())
+		}
+		return 0
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := findFiles(formulaExt, paths...)
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang lint: %s\n", err)
+		return 2
+	}
+
+	code := 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "wflang lint: %s\n", err)
+			code = 1
+			continue
+		}
+		prs := parser.New(lexer.New(string(src)))
+		if len(prs.Errors()) > 0 {
+			fmt.Fprintf(stderr, "wflang lint: %s: cannot lint a formula with syntax errors\n", file)
+			code = 1
+			continue
+		}
+		sup := lint.NewSuppressor(prs.Tokens())
+		diags := append(sup.Filter(lint.Lint(prs, cfg)), sup.Diagnostics(lint.IsRule)...)
+		sort.SliceStable(diags, func(i, j int) bool { return diags[i].Start.LT(diags[j].Start) })
+		for _, d := range diags {
+			fmt.Fprintf(stdout, "%s:%d:%d: %s: %s (%s)\n", file, d.Start.Line+1, d.Start.Col+1, d.Severity, d.Msg, d.Rule)
+			code = 1
+		}
+	}
+	return code
+}
+
+// withSeverities returns cfg with the severities set by flags.
+func withSeverities(cfg, flags lint.Config) lint.Config {
+	cfg = maps.Clone(cfg)
+	if cfg == nil {
+		cfg = lint.Config{}
+	}
+	for id, rc := range flags {
+		prev := cfg[id]
+		prev.Severity = rc.Severity
+		cfg[id] = prev
+	}
+	return cfg
+}
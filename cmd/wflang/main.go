@@ -0,0 +1,46 @@
+// Command wflang is a command line tool for working with WFLang formulas.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: wflang <command> [arguments]
+
+commands:
+  check   report syntax errors, type errors and lint diagnostics of formulas
+  fmt     format formulas
+  lint    report likely mistakes and style issues in formulas
+  test    run .wftest golden-result tests of formulas
+`
+
+// command runs a subcommand with its arguments and returns the exit code.
+type command func(args []string, stdout, stderr io.Writer) int
+
+func commands() map[string]command {
+	return map[string]command{
+		"check": runCheck,
+		"fmt":   runFmt,
+		"lint":  runLint,
+		"test":  runTest,
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, ok := commands()[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "wflang: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	return cmd(args[1:], stdout, stderr)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/scatternoodle/wflang/wflang/wftest"
+)
+
+// runTest runs the test files at the given paths, which default to the current
+// directory. It exits 1 if any case fails, and 2 if the tests cannot be run.
+func runTest(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: wflang test [-v] [-run regexp] [paths...]")
+		flags.PrintDefaults()
+	}
+	verbose := flags.Bool("v", false, "print passing cases as well as failures")
+	runPattern := flags.String("run", "", "only run cases whose name matches `regexp`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	filter, err := regexp.Compile(*runPattern)
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang test: -run: %s\n", err)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := wftest.Find(paths...)
+	if err != nil {
+		fmt.Fprintf(stderr, "wflang test: %s\n", err)
+		return 2
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(stderr, "wflang test: no %s files found\n", wftest.Ext)
+		return 2
+	}
+
+	var passed, failed int
+	for _, file := range files {
+		suite, err := wftest.Load(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "wflang test: %s\n", err)
+			return 2
+		}
+		var cases []wftest.Case
+		for _, c := range suite.Cases {
+			if filter.MatchString(c.Name) {
+				cases = append(cases, c)
+			}
+		}
+		suite.Cases = cases
+
+		for _, res := range suite.Run() {
+			if res.Pass {
+				passed++
+				if *verbose {
+					fmt.Fprintf(stdout, "--- PASS: %s: %s\n", res.Suite, res.Name)
+				}
+				continue
+			}
+			failed++
+			fmt.Fprintf(stdout, "--- FAIL: %s: %s\n", res.Suite, res.Name)
+			fmt.Fprintf(stdout, "    want: %s\n", res.Want)
+			fmt.Fprintf(stdout, "    have: %s\n", res.Have)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(stdout, "FAIL\t%d passed, %d failed\n", passed, failed)
+		return 1
+	}
+	fmt.Fprintf(stdout, "ok\t%d passed\n", passed)
+	return 0
+}
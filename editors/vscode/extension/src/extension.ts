@@ -1,6 +1,8 @@
-import { ExtensionContext, window, workspace } from "vscode";
+import { ExtensionContext, FileSystemWatcher, RelativePattern, Uri, window, workspace } from "vscode";
 
 import {
+  DidChangeWatchedFilesNotification,
+  FileChangeType,
   LanguageClient,
   LanguageClientOptions,
   TransportKind,
@@ -15,13 +17,22 @@ const logPath = "editors/vscode/extension/server/logs/server.log";
 
 const selector = { pattern: "**/*.wflang", scheme: "file", language: "wflang" };
 
+const configFile = ".wflang.json";
+
 let client: LanguageClient;
 
+// the server reloads its project configuration when it changes, and when a
+// catalogue it names changes. The catalogues are watched by catalogWatchers,
+// which are recreated whenever the configuration changes.
+const configWatcher = workspace.createFileSystemWatcher(`**/${configFile}`);
+let catalogWatchers: FileSystemWatcher[] = [];
+
 const outputChannel = window.createOutputChannel("wflang");
 const clientOptions: LanguageClientOptions = {
   documentSelector: [selector],
   synchronize: {
-    fileEvents: workspace.createFileSystemWatcher("**/.clientrc"),
+    fileEvents: configWatcher,
+    configurationSection: "wflang",
   },
   outputChannel: outputChannel,
 };
@@ -34,11 +45,49 @@ const serverOptions: ServerOptions = {
   },
   debug: {
     command: serverPath,
-    args: [logPath],
+    args: ["-debug", logPath],
     transport: TransportKind.stdio,
   },
 };
 
+// catalogPaths returns the absolute paths of the catalogue files named by the
+// configuration file at uri, or none if it cannot be read.
+function catalogPaths(uri: Uri): string[] {
+  let catalogs: Record<string, string[] | undefined>;
+  try {
+    catalogs = JSON.parse(fs.readFileSync(uri.fsPath, "utf8")).catalogs ?? {};
+  } catch (e) {
+    console.error(`Error reading ${uri.fsPath}: ${e}`);
+    return [];
+  }
+  const dir = path.dirname(uri.fsPath);
+  return ["policySets", "payCodes", "employeeAttributes", "macros"].flatMap((kind) =>
+    (catalogs[kind] ?? []).map((p) => path.resolve(dir, p)),
+  );
+}
+
+// watchCatalogs replaces catalogWatchers with watchers of the catalogue files
+// named by the configuration files of the workspace, which forward their changes
+// to the server.
+async function watchCatalogs(): Promise<void> {
+  catalogWatchers.forEach((w) => w.dispose());
+  catalogWatchers = [];
+
+  const notify = (type: FileChangeType) => (uri: Uri) =>
+    client.sendNotification(DidChangeWatchedFilesNotification.type, {
+      changes: [{ uri: uri.toString(), type: type }],
+    });
+  for (const config of await workspace.findFiles(`**/${configFile}`)) {
+    for (const p of catalogPaths(config)) {
+      const watcher = workspace.createFileSystemWatcher(new RelativePattern(path.dirname(p), path.basename(p)));
+      watcher.onDidCreate(notify(FileChangeType.Created));
+      watcher.onDidChange(notify(FileChangeType.Changed));
+      watcher.onDidDelete(notify(FileChangeType.Deleted));
+      catalogWatchers.push(watcher);
+    }
+  }
+}
+
 function validateLogPath(): boolean {
   const logDir = path.dirname(logPath);
 
@@ -62,7 +111,12 @@ export function activate(context: ExtensionContext) {
   }
 
   client = new LanguageClient("wflang", "WF Language Server", serverOptions, clientOptions);
-  client.start();
+  client.start().then(watchCatalogs);
+
+  configWatcher.onDidCreate(watchCatalogs);
+  configWatcher.onDidChange(watchCatalogs);
+  configWatcher.onDidDelete(watchCatalogs);
+  context.subscriptions.push(configWatcher, { dispose: () => catalogWatchers.forEach((w) => w.dispose()) });
 }
 
 export function deactivate() {
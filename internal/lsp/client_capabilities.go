@@ -2,4 +2,14 @@ package lsp
 
 type ClientCapabilities struct {
 	// WIP...
+	General *GeneralClientCapabilities `json:"general,omitempty"`
+}
+
+// GeneralClientCapabilities
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#clientCapabilities
+type GeneralClientCapabilities struct {
+	// The position encodings supported by the client, in order of preference. If
+	// omitted, only utf-16 is supported.
+	PositionEncodings []PositionEncodingKind `json:"positionEncodings,omitempty"`
 }
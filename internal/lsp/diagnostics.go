@@ -0,0 +1,48 @@
+package lsp
+
+import "github.com/scatternoodle/wflang/internal/jrpc2"
+
+// PublishDiagnosticsNotification is sent from the server to the client to signal
+// the results of validation runs. Newly pushed diagnostics always replace those
+// previously pushed for the same document, so an empty list clears them.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_publishDiagnostics
+type PublishDiagnosticsNotification struct {
+	jrpc2.Notification
+	Params PublishDiagnosticsParams `json:"params"`
+}
+
+// PublishDiagnosticsParams - see PublishDiagnosticsNotification
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#publishDiagnosticsParams
+type PublishDiagnosticsParams struct {
+	URI string `json:"uri"`
+	// Optional version number of the document the diagnostics are published for.
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic represents a diagnostic, such as a compiler error or warning.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnostic
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity,omitempty"`
+	Code     string             `json:"code,omitempty"`
+	// A human-readable string describing the source of this diagnostic.
+	Source  string `json:"source,omitempty"`
+	Message string `json:"message"`
+}
+
+// DiagnosticSeverity
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnosticSeverity
+type DiagnosticSeverity int
+
+const (
+	_ DiagnosticSeverity = iota
+	SeverityError
+	SeverityWarning
+	SeverityInformation
+	SeverityHint
+)
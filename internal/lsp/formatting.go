@@ -0,0 +1,56 @@
+package lsp
+
+import "github.com/scatternoodle/wflang/internal/jrpc2"
+
+// Document Formatting
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_formatting
+
+// DocumentFormattingRequest
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_formatting
+type DocumentFormattingRequest struct {
+	jrpc2.Request
+	DocumentFormattingParams `json:"params"`
+}
+
+// DocumentFormattingParams
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentFormattingParams
+type DocumentFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Options      FormattingOptions      `json:"options"`
+}
+
+// DocumentRangeFormattingRequest
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_rangeFormatting
+type DocumentRangeFormattingRequest struct {
+	jrpc2.Request
+	DocumentRangeFormattingParams `json:"params"`
+}
+
+// DocumentRangeFormattingParams
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentRangeFormattingParams
+type DocumentRangeFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Options      FormattingOptions      `json:"options"`
+}
+
+// FormattingOptions describes what options formatting should use. Only the
+// properties defined by the spec are decoded.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#formattingOptions
+type FormattingOptions struct {
+	TabSize      uint `json:"tabSize"`
+	InsertSpaces bool `json:"insertSpaces"`
+}
+
+// DocumentFormattingResponse is the response to both formatting and range
+// formatting requests. A nil Result means there are no changes to make.
+type DocumentFormattingResponse struct {
+	jrpc2.Response
+	Result []TextEdit `json:"result"`
+}
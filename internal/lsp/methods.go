@@ -8,15 +8,24 @@ const (
 	MethodDocDidOpen         string = "textDocument/didOpen"
 	MethodDocDidChange       string = "textDocument/didChange"
 	MethodDocDidSave         string = "textDocument/didSave"
+	MethodDocDidClose        string = "textDocument/didClose"
 	MethodSemanticTokensFull string = "textDocument/semanticTokens/full"
 	MethodHover              string = "textDocument/hover"
 	MethodDocumentSymbols    string = "textDocument/documentSymbol"
 	MethodDeclaration        string = "textDocument/declaration"
 	MethodDefinition         string = "textDocument/definition"
+	MethodReferences         string = "textDocument/references"
+	MethodDocumentHighlight  string = "textDocument/documentHighlight"
 	MethodShowMessage        string = "window/showMessage"
 	MethodCompletion         string = "textDocument/completion"
 	MethodRename             string = "textDocument/rename"
+	MethodPrepareRename      string = "textDocument/prepareRename"
 	MethodSignatureHelp      string = "textDocument/signatureHelp"
+	MethodFormatting         string = "textDocument/formatting"
+	MethodRangeFormatting    string = "textDocument/rangeFormatting"
+	MethodPublishDiagnostics string = "textDocument/publishDiagnostics"
+	MethodDidChangeConfig    string = "workspace/didChangeConfiguration"
+	MethodDidChangeWatched   string = "workspace/didChangeWatchedFiles"
 	MethodSetTrace           string = "$/setTrace"
 	MethodLogTrace           string = "$/logTrace"
 )
@@ -0,0 +1,69 @@
+package lsp
+
+import "github.com/scatternoodle/wflang/internal/jrpc2"
+
+// ReferencesRequest
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_references
+type ReferencesRequest struct {
+	jrpc2.Request
+	Params ReferenceParams `json:"params"`
+}
+
+// ReferenceParams
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#referenceParams
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context ReferenceContext `json:"context"`
+}
+
+// ReferenceContext
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#referenceContext
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+// ReferencesResponse
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_references
+type ReferencesResponse struct {
+	jrpc2.Response
+	Result []Location `json:"result"`
+}
+
+// DocumentHighlightRequest
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_documentHighlight
+type DocumentHighlightRequest struct {
+	jrpc2.Request
+	Params TextDocumentPositionParams `json:"params"`
+}
+
+// DocumentHighlightResponse
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_documentHighlight
+type DocumentHighlightResponse struct {
+	jrpc2.Response
+	Result []DocumentHighlight `json:"result"`
+}
+
+// DocumentHighlight
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentHighlight
+type DocumentHighlight struct {
+	Range Range                 `json:"range"`
+	Kind  DocumentHighlightKind `json:"kind,omitempty"`
+}
+
+// DocumentHighlightKind
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentHighlightKind
+type DocumentHighlightKind int
+
+const (
+	DOC_HIGHLIGHT_KIND_TEXT  DocumentHighlightKind = 1 // a textual occurrence.
+	DOC_HIGHLIGHT_KIND_READ  DocumentHighlightKind = 2 // read-access of a symbol.
+	DOC_HIGHLIGHT_KIND_WRITE DocumentHighlightKind = 3 // write-access of a symbol.
+)
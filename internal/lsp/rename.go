@@ -29,3 +29,27 @@ type RenameResponse struct {
 	jrpc2.Response
 	Result *WorkspaceEdit `json:"result,omitempty"`
 }
+
+// RenameOptions
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#renameOptions
+type RenameOptions struct {
+	PrepareProvider bool `json:"prepareProvider,omitempty"`
+}
+
+// PrepareRenameRequest
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_prepareRename
+type PrepareRenameRequest struct {
+	jrpc2.Request
+	Params TextDocumentPositionParams `json:"params"`
+}
+
+// PrepareRenameResponse holds the range of the name to be renamed, or null if
+// there is nothing to rename at the position.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_prepareRename
+type PrepareRenameResponse struct {
+	jrpc2.Response
+	Result *Range `json:"result"`
+}
@@ -15,7 +15,11 @@ type TokenTypesLegend struct {
 
 type SemanticTokensRequest struct {
 	jrpc2.Request
-	TextDocumentIdentifier `json:"textDocument"`
+	Params SemanticTokensParams `json:"params"`
+}
+
+type SemanticTokensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
 }
 
 type SemanticTokensResponse struct {
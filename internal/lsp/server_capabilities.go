@@ -1,14 +1,19 @@
 package lsp
 
 type ServerCapabilities struct {
-	TextDocumentSync       TextDocumentSyncKind  `json:"textDocumentSync,omitempty"`
-	SemanticTokensProvider SemanticTokensOptions `json:"semanticTokensProvider,omitempty"`
-	HoverProvider          bool                  `json:"hoverProvider,omitempty"`
-	DocumentSymbolProvider bool                  `json:"documentSymbolProvider,omitempty"`
-	DefinitionProvider     bool                  `json:"definitionProvider,omitempty"`
-	CompletionProvider     CompletionOptions     `json:"completionProvider,omitempty"`
-	RenameProvider         bool                  `json:"renameProvider,omitempty"`
-	SignatureHelpProvider  *SignatureHelpOptions `json:"signatureHelpProvider,omitempty"`
+	PositionEncoding                PositionEncodingKind  `json:"positionEncoding,omitempty"`
+	TextDocumentSync                TextDocumentSyncKind  `json:"textDocumentSync,omitempty"`
+	SemanticTokensProvider          SemanticTokensOptions `json:"semanticTokensProvider,omitempty"`
+	HoverProvider                   bool                  `json:"hoverProvider,omitempty"`
+	DocumentSymbolProvider          bool                  `json:"documentSymbolProvider,omitempty"`
+	DefinitionProvider              bool                  `json:"definitionProvider,omitempty"`
+	ReferencesProvider              bool                  `json:"referencesProvider,omitempty"`
+	CompletionProvider              CompletionOptions     `json:"completionProvider,omitempty"`
+	RenameProvider                  *RenameOptions        `json:"renameProvider,omitempty"`
+	SignatureHelpProvider           *SignatureHelpOptions `json:"signatureHelpProvider,omitempty"`
+	DocumentFormattingProvider      bool                  `json:"documentFormattingProvider,omitempty"`
+	DocumentRangeFormattingProvider bool                  `json:"documentRangeFormattingProvider,omitempty"`
+	DocumentHighlightProvider       bool                  `json:"documentHighlightProvider,omitempty"`
 }
 
 type TextDocumentSyncKind int
@@ -38,8 +38,25 @@ type NotificationDidChangeParams struct {
 	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
 }
 
+type NotificationDidClose struct {
+	jrpc2.Notification
+	Params NotificationDidCloseParams `json:"params"`
+}
+
+type NotificationDidCloseParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// TextDocumentContentChangeEvent describes a change to a text document. If Range
+// is nil, Text is the new full content of the document, otherwise Text replaces
+// the given range.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocumentContentChangeEvent
 type TextDocumentContentChangeEvent struct {
-	Text string `json:"text"`
+	Range *Range `json:"range,omitempty"`
+	// Deprecated: use Range instead.
+	RangeLength *uint  `json:"rangeLength,omitempty"`
+	Text        string `json:"text"`
 }
 
 type TextDocumentPositionParams struct {
@@ -47,11 +64,28 @@ type TextDocumentPositionParams struct {
 	Position               `json:"position"`
 }
 
+// Position in a text document expressed as zero-based line and character offset.
+// The unit of the character offset is determined by the negotiated
+// PositionEncodingKind.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#position
 type Position struct {
 	Line uint `json:"line"`
 	Col  uint `json:"character"`
 }
 
+// PositionEncodingKind is the unit in which the character offset of a Position is
+// counted.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#positionEncodingKind
+type PositionEncodingKind string
+
+const (
+	PositionEncodingUTF8  PositionEncodingKind = "utf-8"
+	PositionEncodingUTF16 PositionEncodingKind = "utf-16" // default, must always be supported.
+	PositionEncodingUTF32 PositionEncodingKind = "utf-32"
+)
+
 // Range represents a range in a text document expressed as (zero-based) start and
 // end positions.
 //
@@ -0,0 +1,45 @@
+package lsp
+
+import "github.com/scatternoodle/wflang/internal/jrpc2"
+
+// DidChangeConfigurationNotification is sent from the client to the server when
+// the client's settings change. The settings are not used by the server, which
+// reloads its configuration file instead.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspace_didChangeConfiguration
+type DidChangeConfigurationNotification struct {
+	jrpc2.Notification
+	Params DidChangeConfigurationParams `json:"params"`
+}
+
+type DidChangeConfigurationParams struct {
+	Settings any `json:"settings"`
+}
+
+// DidChangeWatchedFilesNotification is sent from the client to the server when
+// files it watches change.
+//
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspace_didChangeWatchedFiles
+type DidChangeWatchedFilesNotification struct {
+	jrpc2.Notification
+	Params DidChangeWatchedFilesParams `json:"params"`
+}
+
+type DidChangeWatchedFilesParams struct {
+	Changes []FileEvent `json:"changes"`
+}
+
+// FileEvent describes a change to a watched file.
+type FileEvent struct {
+	URI  string         `json:"uri"`
+	Type FileChangeType `json:"type"`
+}
+
+type FileChangeType int
+
+const (
+	_ FileChangeType = iota
+	FileCreated
+	FileChanged
+	FileDeleted
+)
@@ -1,13 +1,24 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/catalog"
 	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
 )
 
 // TODO: only partially implemented.
-func (srv *Server) completions(pos lsp.Position) []lsp.CompletionItem {
-	_ = pos
+func (doc *document) completions(pos lsp.Position) []lsp.CompletionItem {
+	if doc.isMemberAccess(pos) {
+		// builtins are never valid members, only the fields and methods of records.
+		return doc.fieldCompletions(pos)
+	}
+	if items, ok := doc.catalogCompletions(pos); ok {
+		return items
+	}
 	funcs := object.Builtins()
 	items := make([]lsp.CompletionItem, 0, len(funcs))
 	for _, fn := range funcs {
@@ -31,3 +42,194 @@ func (srv *Server) completions(pos lsp.Position) []lsp.CompletionItem {
 	}
 	return items
 }
+
+// isMemberAccess returns true if the cursor at pos is completing the member of a
+// member expression, i.e. directly after a period or within the member ident.
+func (doc *document) isMemberAccess(pos lsp.Position) bool {
+	idx, tok, ok := doc.getTokenAtPos(cursorPos(pos))
+	if !ok {
+		return false
+	}
+	if tok.Type == token.T_PERIOD {
+		return true
+	}
+	return idx > 0 && doc.parser.Tokens()[idx-1].Type == token.T_PERIOD
+}
+
+// tokensBefore returns the tokens that start before the cursor at pos, without
+// comments, and the position of the cursor.
+func (doc *document) tokensBefore(pos lsp.Position) ([]token.Token, token.Pos, bool) {
+	cursor, err := doc.conv.fromLSP(pos)
+	if err != nil {
+		return nil, token.Pos{}, false
+	}
+	var toks []token.Token
+	for _, tok := range doc.parser.Tokens() {
+		if tok.StartPos.LT(cursor) && !tok.IsComment() {
+			toks = append(toks, tok)
+		}
+	}
+	return toks, cursor, true
+}
+
+// fieldCompletions returns the fields and methods of the record bound to the alias
+// whose member is being completed at pos, or no items if it is not an alias of
+// records.
+func (doc *document) fieldCompletions(pos lsp.Position) []lsp.CompletionItem {
+	items := []lsp.CompletionItem{}
+	toks, _, ok := doc.tokensBefore(pos)
+	if !ok {
+		return items
+	}
+	i := len(toks) - 1
+	if i >= 0 && toks[i].Type == token.T_IDENT {
+		i-- // the field being typed.
+	}
+	if i < 1 || toks[i].Type != token.T_PERIOD || toks[i-1].Type != token.T_IDENT {
+		return items
+	}
+	record, ok := aliasRecords(toks[:i-1], toks[i-1].Literal)
+	if !ok {
+		return items
+	}
+	for _, field := range doc.parser.Schemas()[record] {
+		item := catalogItem(field.Name, field.Name, field.Desc, lsp.CompItemField)
+		item.Detail = string(field.Type)
+		items = append(items, item)
+	}
+	for _, method := range object.RecordMethods()[record] {
+		item := catalogItem(method.Name, method.Name+"()", "", lsp.CompItemMethod)
+		item.Detail = string(method.ReturnType)
+		item.Documentation = &lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: method.Doc().String()}
+		items = append(items, item)
+	}
+	return items
+}
+
+// aliasRecords returns the type of record bound to the alias name by the closest
+// summary function in toks that declares it.
+func aliasRecords(toks []token.Token, name string) (types.Type, bool) {
+	for i := len(toks) - 1; i > 0; i-- {
+		if toks[i-1].Type != token.T_ALIAS || toks[i].Type != token.T_IDENT || !strings.EqualFold(toks[i].Literal, name) {
+			continue
+		}
+		depth := 0 // of the parens closed between the call and the alias.
+		for j := i - 2; j > 0; j-- {
+			switch toks[j].Type {
+			case token.T_RPAREN:
+				depth++
+			case token.T_LPAREN:
+				if depth > 0 {
+					depth--
+					continue
+				}
+				if toks[j-1].Type != token.T_BUILTIN {
+					return "", false
+				}
+				fn, ok := object.Builtin(toks[j-1].Literal)
+				return fn.Records, ok && fn.Records != ""
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// catalogCompletions returns the names of the catalog that are valid at pos: the
+// policy sets after "in set", the pay codes in the list of an in expression on a
+// pay code field, the employee attributes in the first argument of calls that take
+// one, and the macros after the opening "$" of a macro. Returns false if pos is in
+// none of them.
+func (doc *document) catalogCompletions(pos lsp.Position) ([]lsp.CompletionItem, bool) {
+	toks, cursor, ok := doc.tokensBefore(pos)
+	if !ok {
+		return nil, false
+	}
+	cat := doc.parser.Catalog()
+
+	last := len(toks) - 1
+	if last >= 0 && toks[last].Type == token.T_IDENT {
+		last-- // the name being typed.
+	}
+	if last >= 0 && toks[last].Type == token.T_SET {
+		items := []lsp.CompletionItem{}
+		for _, set := range cat.PolicySets() {
+			items = append(items, catalogItem(set.Name, set.Name, set.Description, lsp.CompItemEnum))
+		}
+		return items, true
+	}
+	if last >= 0 && toks[last].Type == token.T_DOLLAR && (last == 0 || toks[last-1].Type != token.T_RPAREN) {
+		items := []lsp.CompletionItem{}
+		for _, macro := range cat.Macros() {
+			item := catalogItem(macro.Name, macro.Name, macro.Description, lsp.CompItemSnippet)
+			item.Detail = string(macro.Type)
+			items = append(items, item)
+		}
+		return items, true
+	}
+
+	quote := `"`
+	if len(toks) > 0 && toks[len(toks)-1].Type == token.T_STRING && toks[len(toks)-1].EndPos.GTE(cursor) {
+		quote = "" // completing within a string.
+	}
+
+	if items, ok := attributeCompletions(toks, cat, quote); ok {
+		return items, true
+	}
+	i := len(toks) - 1
+	for i >= 0 && (toks[i].Type == token.T_STRING || toks[i].Type == token.T_COMMA) {
+		i--
+	}
+	if i < 3 || toks[i].Type != token.T_LBRACKET || toks[i-1].Type != token.T_IN ||
+		!strings.EqualFold(toks[i-2].Literal, object.FieldPayCode) || toks[i-3].Type != token.T_PERIOD {
+		return nil, false
+	}
+	items := []lsp.CompletionItem{}
+	for _, pc := range cat.PayCodes() {
+		items = append(items, catalogItem(pc.Code, quote+pc.Code+quote, pc.Description, lsp.CompItemValue))
+	}
+	return items, true
+}
+
+// attributeCompletions returns the employee attributes of cat if toks, which
+// precede the cursor, end within the first argument of a call that takes one.
+// Attributes are idents, except for getAttributeCalculationDate, which takes a
+// string quoted by quote.
+func attributeCompletions(toks []token.Token, cat *catalog.Catalog, quote string) ([]lsp.CompletionItem, bool) {
+	i := len(toks) - 1
+	if i >= 0 && (toks[i].Type == token.T_IDENT || toks[i].Type == token.T_STRING) {
+		i-- // the name being typed.
+	}
+	if i < 1 || toks[i].Type != token.T_LPAREN || toks[i-1].Type != token.T_BUILTIN {
+		return nil, false
+	}
+	switch strings.ToLower(toks[i-1].Literal) {
+	case object.EmployeeAttribute, object.EmployeeAttributeExists:
+		quote = ""
+	case object.GetAttributeCalcDate:
+	default:
+		return nil, false
+	}
+	items := []lsp.CompletionItem{}
+	for _, attr := range cat.Attributes() {
+		item := catalogItem(attr.Name, quote+attr.Name+quote, attr.Description, lsp.CompItemConstant)
+		item.Detail = string(attr.Type)
+		items = append(items, item)
+	}
+	return items, true
+}
+
+// catalogItem returns the completion item of a name from the catalog.
+func catalogItem(label, insert, description string, kind lsp.CompletionItemKind) lsp.CompletionItem {
+	item := lsp.CompletionItem{
+		Label:          label,
+		Kind:           kind,
+		InsertText:     insert,
+		InsertFormat:   lsp.InsFormatPlainText,
+		InsertTextMode: lsp.InsModeAsIs,
+	}
+	if description != "" {
+		item.LabelDetails = &lsp.CompletionItemLabelDetails{Description: description}
+	}
+	return item
+}
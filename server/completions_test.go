@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestCatalogCompletions(t *testing.T) {
+	cat := &catalog.Catalog{}
+	cat.AddPolicySet(catalog.PolicySet{Name: "OT_CODES"})
+	cat.AddPolicySet(catalog.PolicySet{Name: "LEAVE"})
+	cat.AddPayCode(catalog.PayCode{Code: "REG", Description: "Regular"})
+	cat.AddPayCode(catalog.PayCode{Code: "OT"})
+	cat.AddAttribute(catalog.Attribute{Name: "UNION", Type: types.T_STRING})
+	cat.AddAttribute(catalog.Attribute{Name: "HOURLY_RATE", Type: types.T_NUMBER})
+	cat.AddMacro(catalog.Macro{Name: "GO_LIVE_DATE", Type: types.T_DATE})
+	cat.AddMacro(catalog.Macro{Name: "BASE_HOURS", Type: types.T_ANY})
+
+	tests := []struct {
+		name  string
+		input string
+		col   uint
+		want  []string // the insert text of each item, or nil for the usual completions.
+	}{
+		{"set", `count(over day alias d, where d.pay_code in set )`, 48, []string{"LEAVE", "OT_CODES"}},
+		{"set name", `count(over day alias d, where d.pay_code in set OT)`, 50, []string{"LEAVE", "OT_CODES"}},
+		{"list", `count(over day alias d, where d.pay_code in [])`, 45, []string{`"OT"`, `"REG"`}},
+		{"list item", `count(over day alias d, where d.pay_code in ["REG", ])`, 52, []string{`"OT"`, `"REG"`}},
+		{"in string", `count(over day alias d, where d.pay_code in ["RE"])`, 48, []string{"OT", "REG"}},
+		{"attribute", `employee_attribute()`, 19, []string{"HOURLY_RATE", "UNION"}},
+		{"attribute name", `employee_attribute_exists(UN, day)`, 28, []string{"HOURLY_RATE", "UNION"}},
+		{"attribute string", `getAttributeCalculationDate(, day)`, 28, []string{`"HOURLY_RATE"`, `"UNION"`}},
+		{"second argument", `employee_attribute(UNION, )`, 26, nil},
+		{"macro", `$`, 1, []string{"BASE_HOURS", "GO_LIVE_DATE"}},
+		{"macro name", `1 + $GO_L`, 9, []string{"BASE_HOURS", "GO_LIVE_DATE"}},
+		{"after macro", `$GO_LIVE_DATE()$`, 16, nil},
+		{"other field", `count(over day alias d, where d.comment in [])`, 44, nil},
+		{"elsewhere", `count(over day alias d, where d.pay_code in ["REG"])`, 6, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: tt.input}, lsp.PositionEncodingUTF16, parser.WithCatalog(cat))
+			items, ok := doc.catalogCompletions(lsp.Position{Line: 0, Col: tt.col})
+			if tt.want == nil {
+				if ok {
+					t.Errorf("have %+v, want no catalog completions", items)
+				}
+				return
+			}
+			var have []string
+			for _, item := range items {
+				have = append(have, item.InsertText)
+			}
+			if fmt.Sprint(have) != fmt.Sprint(tt.want) {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldCompletions(t *testing.T) {
+	times := []string{"WORK_DT", "PAY_CODE", "HOURS", "START_DTTM", "END_DTTM", "WORK_RULE", "hasTimes", "timeRange"}
+	tests := []struct {
+		name  string
+		input string
+		col   uint
+		want  []string
+	}{
+		{"time", `sumTime(over day alias x, x.)`, 28, times},
+		{"field name", `sumTime(over day alias x, x.HO)`, 30, times},
+		{"exception", `countException(over day alias e, where e.)`, 41, []string{"WORK_DT", "EXCEPTION_CODE", "SEVERITY", "MESSAGE", "isSeverity"}},
+		{"parens", `countTime(over (day) alias t, where t.)`, 38, times},
+		{"days", `count(over day alias d, where d.)`, 32, nil},
+		{"not an alias", `var x = 1; x.`, 13, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: tt.input}, lsp.PositionEncodingUTF16)
+			var have []string
+			for _, item := range doc.completions(lsp.Position{Line: 0, Col: tt.col}) {
+				have = append(have, item.Label)
+			}
+			if fmt.Sprint(have) != fmt.Sprint(tt.want) {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+}
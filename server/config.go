@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"slices"
+
+	"github.com/scatternoodle/wflang/internal/jrpc2"
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/config"
+)
+
+// uriPath returns the file system path of a file URI.
+func uriPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme %q", u.Scheme)
+	}
+	path := u.Path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:] // a Windows drive, such as /C:/formulas.
+	}
+	return filepath.FromSlash(path), nil
+}
+
+// setRoot sets the workspace root, whose configuration file the server loads.
+func (srv *Server) setRoot(rootURI string) {
+	root, err := uriPath(rootURI)
+	if err != nil {
+		slog.Error("unable to use workspace root", "uri", rootURI, "error", err)
+		return
+	}
+	srv.configPath = filepath.Join(root, config.FileName)
+}
+
+// loadConfig loads the configuration file of the workspace, if there is one, and
+// the catalogues it names. If either cannot be loaded, the previous configuration
+// is kept.
+func (srv *Server) loadConfig() error {
+	if srv.configPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(srv.configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg, err = config.Config{}, nil
+	}
+	var cat *catalog.Catalog
+	if err == nil {
+		cat, err = cfg.Catalogs.Load()
+	}
+	if err != nil {
+		slog.Error("unable to load configuration", "path", srv.configPath, "error", err)
+		return err
+	}
+	slog.Info("loaded configuration", "path", srv.configPath)
+	srv.config, srv.catalog = cfg, cat
+	return nil
+}
+
+// reloadConfig reloads the configuration file and its catalogues, and reparses
+// open documents with them.
+func (srv *Server) reloadConfig(w io.Writer) {
+	if err := srv.loadConfig(); err != nil {
+		showMessage(w, lsp.Error, fmt.Sprintf("wflang: %s", err))
+		return
+	}
+	for _, doc := range srv.documents {
+		doc = srv.updateDocument(lsp.TextDocumentItem{URI: doc.uri, Version: doc.version, Text: doc.text})
+		publishDiagnostics(w, doc)
+	}
+}
+
+func (srv *Server) handleDidChangeConfigNotification(w io.Writer, c []byte, id *int) {
+	var r lsp.DidChangeConfigurationNotification
+	if !handleParseContent(&r, w, c, id) {
+		return
+	}
+	srv.reloadConfig(w)
+}
+
+func (srv *Server) handleDidChangeWatchedNotification(w io.Writer, c []byte, id *int) {
+	var r lsp.DidChangeWatchedFilesNotification
+	if !handleParseContent(&r, w, c, id) {
+		return
+	}
+	watched := append([]string{srv.configPath}, srv.config.Catalogs.Paths()...)
+	for _, change := range r.Params.Changes {
+		if path, err := uriPath(change.URI); err == nil && slices.Contains(watched, path) {
+			srv.reloadConfig(w)
+			return
+		}
+	}
+}
+
+// showMessage asks the client to show msg to the user.
+func showMessage(w io.Writer, typ lsp.MessageType, msg string) {
+	send(w, lsp.ShowMessageNotification{
+		Notification: jrpc2.NewNotification(lsp.MethodShowMessage),
+		Params:       lsp.ShowMessageParams{Type: typ, Message: msg},
+	})
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/config"
+)
+
+func TestURIPath(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"file:///home/me/formulas", filepath.FromSlash("/home/me/formulas"), false},
+		{"file:///home/me/my%20formulas", filepath.FromSlash("/home/me/my formulas"), false},
+		{"file:///C:/formulas", filepath.FromSlash("C:/formulas"), false},
+		{"untitled:Untitled-1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			have, err := uriPath(tt.uri)
+			if (err != nil) != tt.wantErr || have != tt.want {
+				t.Errorf("have %q, %v, want %q", have, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigReload(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, config.FileName)
+	writeConfig := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(`{"lint": {"unused-var": {"severity": "off"}}, "format": {"indent": 2}}`)
+
+	srv := New(nil, nil, false)
+	srv.setRoot("file://" + filepath.ToSlash(root))
+	if err := srv.loadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if fOpts := formatOptions(lsp.FormattingOptions{TabSize: 8}, srv.config.Format); fOpts.Indent != 2 {
+		t.Errorf("have indent %d, want the configured 2", fOpts.Indent)
+	}
+	doc := srv.updateDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: "var a = 1;\n2"})
+	if diags := doc.diagnostics(); len(diags) != 0 {
+		t.Fatalf("have %+v, want none with the rule off", diags)
+	}
+
+	writeConfig(`{}`)
+	var buf bytes.Buffer
+	msg := fmt.Sprintf(`{"jsonrpc": "2.0", "method": "workspace/didChangeWatchedFiles", "params": {"changes": [{"uri": %q, "type": 2}]}}`, "file://"+filepath.ToSlash(path))
+	srv.handleDidChangeWatchedNotification(&buf, []byte(msg), nil)
+	if !strings.Contains(buf.String(), "unused-var") {
+		t.Errorf("have %s, want the diagnostics republished with the rule on", buf.String())
+	}
+
+	writeConfig(`{"lint": `)
+	buf.Reset()
+	srv.handleDidChangeConfigNotification(&buf, []byte(`{"jsonrpc": "2.0", "method": "workspace/didChangeConfiguration", "params": {"settings": null}}`), nil)
+	if !strings.Contains(buf.String(), "window/showMessage") {
+		t.Errorf("have %s, want an error shown for the invalid configuration", buf.String())
+	}
+}
+
+func TestConfigCatalog(t *testing.T) {
+	root := t.TempDir()
+	codes := filepath.Join(root, "codes.csv")
+	if err := os.WriteFile(codes, []byte("code\nREG\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"catalogs": {"payCodes": ["codes.csv"]}}`
+	if err := os.WriteFile(filepath.Join(root, config.FileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New(nil, nil, false)
+	srv.setRoot("file://" + filepath.ToSlash(root))
+	if err := srv.loadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	input := `count(over day alias d, where d.pay_code in ["OT"])`
+	doc := srv.updateDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input})
+	if diags := doc.diagnostics(); len(diags) != 1 || diags[0].Code != "unknown-pay-code" {
+		t.Fatalf("have %+v, want an unknown pay code", diags)
+	}
+
+	if err := os.WriteFile(codes, []byte("code\nREG\nOT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	msg := fmt.Sprintf(`{"jsonrpc": "2.0", "method": "workspace/didChangeWatchedFiles", "params": {"changes": [{"uri": %q, "type": 2}]}}`, "file://"+filepath.ToSlash(codes))
+	srv.handleDidChangeWatchedNotification(&buf, []byte(msg), nil)
+	if diags := srv.documents["file:///test.wflang"].diagnostics(); len(diags) != 0 {
+		t.Errorf("have %+v, want none once the catalogue has the pay code", diags)
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"io"
+
+	"github.com/scatternoodle/wflang/internal/jrpc2"
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/lint"
+)
+
+const diagnosticSource = "wflang"
+
+// diagnostics converts the diagnostics that analysis.Check found in the document
+// into LSP diagnostics, so that the editor reports the same as wflang check.
+func (doc *document) diagnostics() []lsp.Diagnostic {
+	diags := []lsp.Diagnostic{}
+	for _, d := range doc.diags {
+		diags = append(diags, lsp.Diagnostic{
+			Range:    doc.conv.toRange(d.Start, d.End),
+			Severity: lintSeverity(d.Severity),
+			Code:     d.Code,
+			Source:   diagnosticSource,
+			Message:  d.Msg,
+		})
+	}
+	return diags
+}
+
+func lintSeverity(sev lint.Severity) lsp.DiagnosticSeverity {
+	switch sev {
+	case lint.SeverityError:
+		return lsp.SeverityError
+	case lint.SeverityWarning:
+		return lsp.SeverityWarning
+	case lint.SeverityInfo:
+		return lsp.SeverityInformation
+	}
+	return lsp.SeverityHint
+}
+
+// publishDiagnostics pushes the diagnostics for doc to the client, replacing any
+// previously published. An empty set clears them.
+func publishDiagnostics(w io.Writer, doc *document) {
+	send(w, lsp.PublishDiagnosticsNotification{
+		Notification: jrpc2.NewNotification(lsp.MethodPublishDiagnostics),
+		Params: lsp.PublishDiagnosticsParams{
+			URI:         doc.uri,
+			Version:     &doc.version,
+			Diagnostics: doc.diagnostics(),
+		},
+	})
+}
+
+// clearDiagnostics removes all diagnostics for uri from the client.
+func clearDiagnostics(w io.Writer, uri string) {
+	send(w, lsp.PublishDiagnosticsNotification{
+		Notification: jrpc2.NewNotification(lsp.MethodPublishDiagnostics),
+		Params: lsp.PublishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: []lsp.Diagnostic{},
+		},
+	})
+}
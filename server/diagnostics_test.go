@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/lint"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []lsp.Diagnostic
+	}{
+		{
+			name:  "valid",
+			input: `var x = 1; x + 2`,
+			want:  []lsp.Diagnostic{},
+		},
+		{
+			name:  "syntax error",
+			input: `var x = ;`,
+			want: []lsp.Diagnostic{{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Col: 8},
+					End:   lsp.Position{Line: 0, Col: 9},
+				},
+				Severity: lsp.SeverityError,
+				Code:     "syntax-error",
+				Source:   diagnosticSource,
+				Message:  "no prefix parser mapped for token type ;",
+			}},
+		},
+		{
+			name:  "type mismatch",
+			input: `"abc" + 1`,
+			want: []lsp.Diagnostic{{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Col: 0},
+					End:   lsp.Position{Line: 0, Col: 9},
+				},
+				Severity: lsp.SeverityError,
+				Code:     "type-mismatch",
+				Source:   diagnosticSource,
+				Message:  "operator +: mismatched types string and number",
+			}},
+		},
+		{
+			name:  "undefined",
+			input: `var total = 1; totl + total`,
+			want: []lsp.Diagnostic{{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Col: 15},
+					End:   lsp.Position{Line: 0, Col: 19},
+				},
+				Severity: lsp.SeverityError,
+				Code:     "undefined",
+				Source:   diagnosticSource,
+				Message:  "undefined: totl",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := New(nil, nil, false)
+			have := srv.updateDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: tt.input}).diagnostics()
+			if len(have) != len(tt.want) {
+				t.Fatalf("len: have %d, want %d: %v", len(have), len(tt.want), have)
+			}
+			for i := range have {
+				if have[i] != tt.want[i] {
+					t.Errorf("diagnostic %d: have %+v, want %+v", i, have[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintDiagnostics(t *testing.T) {
+	srv := New(nil, nil, false)
+	input := "var unused = 1;\n2"
+	want := lsp.Diagnostic{
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 0, Col: 4},
+			End:   lsp.Position{Line: 0, Col: 10},
+		},
+		Severity: lsp.SeverityWarning,
+		Code:     "unused-var",
+		Source:   diagnosticSource,
+	}
+
+	doc := srv.updateDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input})
+	have := doc.diagnostics()
+	if len(have) != 1 {
+		t.Fatalf("have %+v, want one diagnostic", have)
+	}
+	have[0].Message = ""
+	if have[0] != want {
+		t.Errorf("have %+v, want %+v", have[0], want)
+	}
+
+	srv.config.Lint = lint.Config{"unused-var": {Severity: lint.SeverityOff}}
+	if have := srv.updateDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}).diagnostics(); len(have) != 0 {
+		t.Errorf("have %+v, want none with the rule off", have)
+	}
+}
+
+func TestClearDiagnostics(t *testing.T) {
+	var buf bytes.Buffer
+	clearDiagnostics(&buf, "file:///test.wflang")
+
+	msg := buf.String()
+	for _, want := range []string{`"method":"textDocument/publishDiagnostics"`, `"diagnostics":[]`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %s does not contain %s", msg, want)
+		}
+	}
+}
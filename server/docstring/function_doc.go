@@ -1,13 +1,21 @@
 package docstring
 
+const (
+	codeBlockStart   string = "```wflang\nSYNTAX:\n"
+	codeBlockReturns string = "\nRETURNS: "
+	codeBlockEnd     string = "```\n\n---\n\n"
+)
+
 // FunctionDoc holds various markdown string segments that together comprise the
 // documentation for a function.
 type FunctionDoc struct {
-	Name      string
-	Signature string
-	Returns   string
-	Desc      string
-	Params    []*ParamDoc
+	Name       string
+	Signature  string
+	Returns    string
+	Desc       string
+	Params     []*ParamDoc
+	Examples   []string
+	Deprecated string
 }
 
 // String returns the full markdown string for the function, incorporating all
@@ -17,11 +25,20 @@ func (f *FunctionDoc) String() string {
 		f.Signature + "\n" +
 		codeBlockReturns + f.Returns + "\n" +
 		codeBlockEnd +
-		"### " + f.Name + "\n\n" +
-		f.Desc + "\n\n"
+		"### " + f.Name + "\n\n"
+	if f.Deprecated != "" {
+		s += "**Deprecated:** " + f.Deprecated + "\n\n"
+	}
+	s += f.Desc + "\n\n"
 	for _, param := range f.Params {
 		s += param.String() + "\n\n"
 	}
+	if len(f.Examples) > 0 {
+		s += "#### Examples\n\n"
+		for _, example := range f.Examples {
+			s += "```wflang\n" + example + "\n```\n\n"
+		}
+	}
 	return s
 }
 
@@ -30,11 +47,23 @@ func (f *FunctionDoc) String() string {
 type ParamDoc struct {
 	Name string
 	// for lsp.SignatureHelp - substring position of the FunctionDoc signature [start,end]
-	Label [2]int
-	Type  string
-	Desc  string
+	Label    [2]int
+	Type     string
+	Desc     string
+	Optional bool
+	// written within the argument of the previous param (e.g. an alias), so has no
+	// argument of its own.
+	Inline bool
 }
 
 func (p *ParamDoc) String() string {
-	return "@param " + p.Name + ": " + p.Type + " - " + p.Desc
+	s := "@param `" + p.Name
+	if p.Optional {
+		s += "?"
+	}
+	s += ": " + p.Type + "`"
+	if p.Desc != "" {
+		s += " - " + p.Desc
+	}
+	return s
 }
@@ -1,26 +1,106 @@
 package server
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 
 	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/analysis"
+	"github.com/scatternoodle/wflang/wflang/ast"
 	"github.com/scatternoodle/wflang/wflang/lexer"
 	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
 )
 
-func (srv *Server) updateDocument(doc lsp.TextDocumentItem) {
-	srv.uri = doc.URI
-	srv.parser = parser.New(lexer.New(doc.Text))
+// document holds the state of a single open text document.
+type document struct {
+	uri      string
+	version  int
+	text     string
+	parser   *parser.Parser
+	ast      *ast.AST
+	resolved *resolve.Info // the declarations of the vars and aliases of ast, and their uses.
+	symbols  []lsp.DocumentSymbol
+	conv     *posConverter
+	diags    []analysis.Diagnostic // the result of analysis.Check, set by updateDocument.
+
+	*tokenEncoder
+}
+
+// newDocument parses item with opts and returns a document holding the results,
+// with LSP positions in the given encoding.
+func newDocument(item lsp.TextDocumentItem, encoding lsp.PositionEncodingKind, opts ...parser.Option) *document {
+	doc := &document{
+		uri:     item.URI,
+		version: item.Version,
+		text:    item.Text,
+		parser:  parser.New(lexer.New(item.Text), opts...),
+		conv:    newPosConverter(item.Text, encoding),
+	}
 	var err error
-	if srv.ast, err = srv.parser.AST(); err != nil {
-		slog.Error("error retrieving new AST", "error", err, "parser errors", srv.parser.Errors())
+	if doc.ast, err = doc.parser.AST(); err != nil {
+		slog.Error("error retrieving new AST", "error", err, "parser errors", doc.parser.Errors())
 	}
-	srv.tokenEncoder = newTokenEncoder(srv.parser.Tokens())
+	doc.resolved = doc.parser.Resolved()
+	doc.tokenEncoder = newTokenEncoder(doc.parser.Tokens(), doc.ast, doc.conv)
 	slog.Info("Document AST generated",
-		"version", doc.Version,
-		"uri", doc.URI,
-		"number of tokens", len(srv.parser.Tokens()),
-		"errors", len(srv.parser.Errors()),
+		"version", doc.version,
+		"uri", doc.uri,
+		"number of tokens", len(doc.parser.Tokens()),
+		"errors", len(doc.parser.Errors()),
 	)
-	srv.createSymbols()
+	doc.createSymbols()
+	return doc
+}
+
+// applyChanges applies the given change events to text in order, and returns the
+// resulting text. Change ranges are in the given position encoding.
+func applyChanges(text string, changes []lsp.TextDocumentContentChangeEvent, encoding lsp.PositionEncodingKind) (string, error) {
+	for i, change := range changes {
+		if change.Range == nil {
+			text = change.Text
+			continue
+		}
+		conv := newPosConverter(text, encoding)
+		start, err := conv.offset(change.Range.Start)
+		if err != nil {
+			return "", fmt.Errorf("change %d: range start: %w", i, err)
+		}
+		end, err := conv.offset(change.Range.End)
+		if err != nil {
+			return "", fmt.Errorf("change %d: range end: %w", i, err)
+		}
+		if end < start {
+			return "", fmt.Errorf("change %d: range end %+v before start %+v", i, change.Range.End, change.Range.Start)
+		}
+		text = text[:start] + change.Text + text[end:]
+	}
+	return text, nil
+}
+
+// updateDocument parses item and stores it, replacing any previous state held for
+// the same URI.
+func (srv *Server) updateDocument(item lsp.TextDocumentItem) *document {
+	doc := newDocument(item, srv.encoding, parser.WithCatalog(srv.catalog), parser.WithSchemas(srv.config.Schemas()))
+	doc.diags = analysis.CheckParsed(doc.uri, doc.text, doc.parser, analysis.Options{Lint: srv.config.Lint}).Diagnostics
+	srv.documents[doc.uri] = doc
+	return doc
+}
+
+// closeDocument discards all state held for uri.
+func (srv *Server) closeDocument(uri string) {
+	delete(srv.documents, uri)
+}
+
+// handleGetDocument returns the open document for uri, or else responds with an
+// error and returns false.
+func (srv *Server) handleGetDocument(w io.Writer, id *int, uri string) (*document, bool) {
+	doc, ok := srv.documents[uri]
+	if !ok {
+		slog.Error("request for unknown document", "uri", uri)
+		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, fmt.Sprintf("document not open: %s", uri), nil)
+		return nil, false
+	}
+	return doc, true
 }
@@ -2,55 +2,103 @@ package server
 
 import (
 	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/resolve"
 	"github.com/scatternoodle/wflang/wflang/token"
 )
 
-func (srv *Server) createSymbols() {
-	srv.symbols = map[string]lsp.DocumentSymbol{}
-
-	for _, v := range srv.parser.Vars() {
-		if v.Statement == nil {
-			continue
+// createSymbols creates the document symbols of the vars of doc. Vars declared in
+// the value of another var are its children.
+func (doc *document) createSymbols() {
+	var vars []*resolve.Decl
+	for _, d := range doc.resolved.Decls {
+		if d.Kind == resolve.KindVar {
+			vars = append(vars, d)
 		}
+	}
+	doc.symbols, _ = doc.varSymbols(vars, nil)
+	if doc.symbols == nil {
+		doc.symbols = []lsp.DocumentSymbol{}
+	}
+}
 
-		varStart, varEnd := v.Statement.Pos()
-
-		// For some reason (at least in VSCode) the end (and the end only) of the larger range in a document
-		// symbol behaves like it's 1-indexed instead of zero-indexed
-		varEnd.Col++
-
-		symbolRange := lsp.Range{
-			Start: lsp.Position{Line: varStart.Line, Col: varStart.Col},
-			End:   lsp.Position{Line: varEnd.Line, Col: varEnd.Col},
+// varSymbols returns the symbols of vars, which are in order of position, up to the
+// first that is not declared within the statement of parent. The rest of vars are
+// returned with them.
+func (doc *document) varSymbols(vars []*resolve.Decl, parent *resolve.Decl) (syms []lsp.DocumentSymbol, rest []*resolve.Decl) {
+	for len(vars) > 0 {
+		d := vars[0]
+		if parent != nil {
+			if start, end := parent.Stmt.Pos(); d.Name.StartPos.LT(start) || d.Name.EndPos.GT(end) {
+				break
+			}
 		}
-
-		nameStart, nameEnd := v.Statement.Name.Pos()
-		selectionRange := lsp.Range{
-			Start: lsp.Position{Line: nameStart.Line, Col: nameStart.Col},
-			End:   lsp.Position{Line: nameEnd.Line, Col: nameEnd.Col + 1},
+		sym := lsp.DocumentSymbol{
+			Name:           d.Name.Value,
+			Kind:           lsp.SYMBOL_KIND_VARIABLE,
+			Range:          doc.conv.toRange(d.Stmt.Pos()),
+			SelectionRange: doc.conv.toRange(d.Name.Pos()),
 		}
+		sym.Children, vars = doc.varSymbols(vars[1:], d)
+		syms = append(syms, sym)
+	}
+	return syms, vars
+}
 
-		srv.symbols[v.Name] = lsp.DocumentSymbol{
-			Name:           v.Name,
-			Kind:           lsp.SYMBOL_KIND_VARIABLE,
-			Range:          symbolRange,
-			SelectionRange: selectionRange,
+// identAtPos returns the token at pos. If it is not an ident, and an ident ends
+// just before pos, as when the cursor ends a word being edited, that ident is
+// returned instead.
+func (doc *document) identAtPos(pos lsp.Position) (token.Token, bool) {
+	_, tok, ok := doc.getTokenAtPos(pos)
+	if !ok || tok.Type != token.T_IDENT {
+		if _, before, found := doc.getTokenAtPos(cursorPos(pos)); found && before.Type == token.T_IDENT {
+			return before, true
 		}
 	}
+	return tok, ok
 }
 
-func (srv *Server) symbolFromPos(pos lsp.Position) (*lsp.DocumentSymbol, bool) {
-	_, tok, ok := srv.getTokenAtPos(pos)
+// declAtPos returns the token at pos, found by identAtPos, and the declaration of
+// the var or alias that it declares or refers to. The token is zero if there is
+// none, and is returned even if it is not an ident that has a declaration.
+func (doc *document) declAtPos(pos lsp.Position) (token.Token, *resolve.Decl, bool) {
+	tok, ok := doc.identAtPos(pos)
 	if !ok {
-		return nil, false
+		return token.Token{}, nil, false
 	}
 	if tok.Type != token.T_IDENT {
-		return nil, false
+		return tok, nil, false
 	}
+	decl, ok := doc.resolved.At(tok.StartPos)
+	return tok, decl, ok
+}
 
-	sym, ok := srv.symbols[tok.Literal]
+// references returns the locations of the idents that refer to the var or alias at
+// pos, preceded by its declaration if includeDecl is true.
+func (doc *document) references(pos lsp.Position, includeDecl bool) []lsp.Location {
+	_, decl, ok := doc.declAtPos(pos)
 	if !ok {
-		return nil, false
+		return nil
+	}
+	locs := []lsp.Location{}
+	if includeDecl {
+		locs = append(locs, lsp.Location{URI: doc.uri, Range: doc.conv.toRange(decl.Name.Pos())})
+	}
+	for _, ident := range doc.resolved.RefsTo(decl) {
+		locs = append(locs, lsp.Location{URI: doc.uri, Range: doc.conv.toRange(ident.Pos())})
+	}
+	return locs
+}
+
+// highlights returns the declaration of the var or alias at pos as a write, and
+// the idents that refer to it as reads.
+func (doc *document) highlights(pos lsp.Position) []lsp.DocumentHighlight {
+	_, decl, ok := doc.declAtPos(pos)
+	if !ok {
+		return nil
+	}
+	hls := []lsp.DocumentHighlight{{Range: doc.conv.toRange(decl.Name.Pos()), Kind: lsp.DOC_HIGHLIGHT_KIND_WRITE}}
+	for _, ident := range doc.resolved.RefsTo(decl) {
+		hls = append(hls, lsp.DocumentHighlight{Range: doc.conv.toRange(ident.Pos()), Kind: lsp.DOC_HIGHLIGHT_KIND_READ})
 	}
-	return &sym, true
+	return hls
 }
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+)
+
+func TestCreateSymbols(t *testing.T) {
+	input := "var a = (var b = 1; b);\nvar c = (var b = 2; b) + (var d = 3; d);\n(var e = a + c; e) + sumTime(over day alias x, x.HOURS)"
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+
+	var names func(syms []lsp.DocumentSymbol) string
+	names = func(syms []lsp.DocumentSymbol) string {
+		s := ""
+		for i, sym := range syms {
+			if i > 0 {
+				s += " "
+			}
+			s += sym.Name
+			if len(sym.Children) > 0 {
+				s += "(" + names(sym.Children) + ")"
+			}
+		}
+		return s
+	}
+	if have, want := names(doc.symbols), "a(b) c(b d) e"; have != want {
+		t.Errorf("have %s, want %s", have, want)
+	}
+}
+
+func TestDeclAtPos(t *testing.T) {
+	input := "(var x = 1; x) + (var x = 2; x) + sumTime(over day alias x, x.HOURS)"
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+
+	tests := []struct {
+		name    string
+		col     uint
+		wantCol uint // of the declaring ident.
+		wantOk  bool
+	}{
+		{"first block", 12, 5, true},
+		{"second block", 29, 22, true},
+		{"declaration", 22, 22, true},
+		{"alias", 60, 57, true},
+		{"field", 63, 0, false},
+		{"builtin", 35, 0, false},
+		{"end of ident", 13, 5, true},
+		{"end of alias", 58, 57, true},
+		{"operator", 15, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, decl, ok := doc.declAtPos(lsp.Position{Line: 0, Col: tt.col})
+			if ok != tt.wantOk {
+				t.Fatalf("have ok %t, want %t", ok, tt.wantOk)
+			}
+			if ok && decl.Name.StartPos.Col != tt.wantCol {
+				t.Errorf("have declaration at col %d, want %d", decl.Name.StartPos.Col, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	input := "// ot is overtime\nvar ot = 1;\n(var ot = 2; ot) + ot + sumTime(over day alias t, t.HOURS) + \"ot\""
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+
+	tests := []struct {
+		name        string
+		pos         lsp.Position
+		includeDecl bool
+		want        string // "line:col" of each location.
+	}{
+		{"declaration", lsp.Position{Line: 1, Col: 4}, true, "1:4 2:19"},
+		{"without declaration", lsp.Position{Line: 1, Col: 4}, false, "2:19"},
+		{"inner", lsp.Position{Line: 2, Col: 13}, true, "2:5 2:13"},
+		{"alias", lsp.Position{Line: 2, Col: 50}, true, "2:47 2:50"},
+		{"comment", lsp.Position{Line: 0, Col: 3}, true, ""},
+		{"field", lsp.Position{Line: 2, Col: 52}, true, ""},
+		{"end of ident", lsp.Position{Line: 2, Col: 15}, true, "2:5 2:13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var have []string
+			for _, loc := range doc.references(tt.pos, tt.includeDecl) {
+				have = append(have, fmt.Sprintf("%d:%d", loc.Range.Start.Line, loc.Range.Start.Col))
+			}
+			if strings.Join(have, " ") != tt.want {
+				t.Errorf("have %v, want %s", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	input := "var ot = 1;\not + (var x = ot; x)"
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+
+	var have []string
+	for _, hl := range doc.highlights(lsp.Position{Line: 1, Col: 0}) {
+		have = append(have, fmt.Sprintf("%d:%d %d", hl.Range.Start.Line, hl.Range.Start.Col, hl.Kind))
+	}
+	want := fmt.Sprintf("0:4 %d, 1:0 %d, 1:14 %d", lsp.DOC_HIGHLIGHT_KIND_WRITE, lsp.DOC_HIGHLIGHT_KIND_READ, lsp.DOC_HIGHLIGHT_KIND_READ)
+	if strings.Join(have, ", ") != want {
+		t.Errorf("have %v, want %s", have, want)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+)
+
+func TestDocumentStore(t *testing.T) {
+	srv := New(nil, nil, false)
+	srv.updateDocument(lsp.TextDocumentItem{URI: "file:///a.wflang", Version: 1, Text: `var a = 1; a`})
+	srv.updateDocument(lsp.TextDocumentItem{URI: "file:///b.wflang", Version: 3, Text: `var b = 2; b`})
+
+	var buf bytes.Buffer
+	id := 1
+	for uri, wantSym := range map[string]string{"file:///a.wflang": "a", "file:///b.wflang": "b"} {
+		doc, ok := srv.handleGetDocument(&buf, &id, uri)
+		if !ok {
+			t.Fatalf("document %s not found", uri)
+		}
+		if len(doc.symbols) != 1 || doc.symbols[0].Name != wantSym {
+			t.Errorf("document %s: have symbols %v, want only %s", uri, doc.symbols, wantSym)
+		}
+	}
+
+	srv.closeDocument("file:///a.wflang")
+	if _, ok := srv.handleGetDocument(&buf, &id, "file:///a.wflang"); ok {
+		t.Fatal("closed document still found")
+	}
+	if buf.Len() == 0 {
+		t.Error("no error response sent for closed document")
+	}
+	if _, ok := srv.documents["file:///b.wflang"]; !ok {
+		t.Error("closing one document removed another")
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	rng := func(sl, sc, el, ec uint) *lsp.Range {
+		return &lsp.Range{Start: lsp.Position{Line: sl, Col: sc}, End: lsp.Position{Line: el, Col: ec}}
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		changes []lsp.TextDocumentContentChangeEvent
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "full",
+			text:    "var x = 1;",
+			changes: []lsp.TextDocumentContentChangeEvent{{Text: "x"}},
+			want:    "x",
+		},
+		{
+			name:    "insert",
+			text:    "var x = 1;\nx",
+			changes: []lsp.TextDocumentContentChangeEvent{{Range: rng(1, 1, 1, 1), Text: " + 2"}},
+			want:    "var x = 1;\nx + 2",
+		},
+		{
+			name:    "replace across lines",
+			text:    "var x = 1;\nvar y = 2;\nx",
+			changes: []lsp.TextDocumentContentChangeEvent{{Range: rng(0, 8, 1, 9), Text: "3"}},
+			want:    "var x = 3;\nx",
+		},
+		{
+			name: "applied in order",
+			text: "abc",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{Range: rng(0, 0, 0, 1), Text: ""},
+				{Range: rng(0, 0, 0, 1), Text: "x"},
+			},
+			want: "xc",
+		},
+		{
+			name:    "crlf line endings",
+			text:    "a\r\nb",
+			changes: []lsp.TextDocumentContentChangeEvent{{Range: rng(0, 1, 0, 5), Text: "z"}},
+			want:    "az\r\nb",
+		},
+		{
+			name:    "utf-16 surrogate pair",
+			text:    `"😀é" + x`,
+			changes: []lsp.TextDocumentContentChangeEvent{{Range: rng(0, 3, 0, 4), Text: "e"}},
+			want:    `"😀e" + x`,
+		},
+		{
+			name:    "line out of range",
+			text:    "x",
+			changes: []lsp.TextDocumentContentChangeEvent{{Range: rng(2, 0, 2, 0), Text: "y"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := applyChanges(tt.text, tt.changes, lsp.PositionEncodingUTF16)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: have %v, want error %t", err, tt.wantErr)
+			}
+			if have != tt.want {
+				t.Fatalf("have %q, want %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestDidChangeVersion(t *testing.T) {
+	srv := New(nil, nil, false)
+	srv.updateDocument(lsp.TextDocumentItem{URI: "file:///a.wflang", Version: 2, Text: "x"})
+
+	change := func(version int, text string) {
+		var buf bytes.Buffer
+		msg := fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{`+
+			`"textDocument":{"uri":"file:///a.wflang","version":%d},`+
+			`"contentChanges":[{"range":{"start":{"line":0,"character":1},"end":{"line":0,"character":1}},"text":%q}]}}`,
+			version, text)
+		srv.handleDocDidChangeNotification(&buf, []byte(msg), nil)
+	}
+
+	change(3, "y")
+	change(3, "z") // duplicate version
+	change(1, "z") // stale version
+	change(4, "w")
+
+	doc := srv.documents["file:///a.wflang"]
+	if doc.text != "xwy" || doc.version != 4 {
+		t.Fatalf("have text %q version %d, want text %q version %d", doc.text, doc.version, "xwy", 4)
+	}
+}
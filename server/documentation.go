@@ -1,20 +1,188 @@
 package server
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/ast"
 	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
 	"github.com/scatternoodle/wflang/wflang/token"
 )
 
-func (srv *Server) hover(pos lsp.Position) lsp.Hover {
-	_, tok, ok := srv.getTokenAtPos(pos)
+func (doc *document) hover(pos lsp.Position) lsp.Hover {
+	_, tok, ok := doc.getTokenAtPos(pos)
 	if !ok {
 		return lsp.Hover{}
 	}
 	if tok.Type == token.T_BUILTIN {
 		return lsp.Hover{MarkupContent: *object.DocMarkdown(strings.ToLower(tok.Literal))}
 	}
+	if hover, ok := doc.attributeHover(tok); ok {
+		return hover
+	}
+	if tok.Type == token.T_IDENT {
+		return doc.identHover(tok)
+	}
 	return lsp.Hover{}
 }
+
+// attributeHover describes the employee attribute named by tok, if tok is the
+// attribute argument of a call and the attribute is in the catalog.
+func (doc *document) attributeHover(tok token.Token) (lsp.Hover, bool) {
+	if doc.ast == nil || (tok.Type != token.T_IDENT && tok.Type != token.T_STRING) {
+		return lsp.Hover{}, false
+	}
+	nodes, err := ast.NodesEnclosing(doc.ast, tok.StartPos)
+	if err != nil {
+		return lsp.Hover{}, false
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		call, ok := nodes[i].(ast.BuiltinCall)
+		if !ok {
+			continue
+		}
+		name, arg, ok := parser.AttributeArg(call)
+		if !ok {
+			return lsp.Hover{}, false
+		}
+		if start, _ := arg.Pos(); start != tok.StartPos {
+			return lsp.Hover{}, false
+		}
+		attr, ok := doc.parser.Catalog().Attribute(name)
+		if !ok {
+			return lsp.Hover{}, false
+		}
+		hover := hoverCode(fmt.Sprintf("(attribute) %s %s", attr.Name, attr.Type))
+		if attr.EffectiveDated {
+			hover.Value += "\n\nEffective dated: the value depends on the date it is read on."
+		}
+		if attr.Description != "" {
+			hover.Value += "\n\n" + attr.Description
+		}
+		return hover, true
+	}
+	return lsp.Hover{}, false
+}
+
+// identHover describes the ident at tok: the field of a record accessed on an
+// alias, or the var or alias that the ident declares or refers to.
+func (doc *document) identHover(tok token.Token) lsp.Hover {
+	if doc.ast == nil {
+		return lsp.Hover{}
+	}
+	nodes, err := ast.NodesEnclosing(doc.ast, tok.StartPos)
+	if err != nil {
+		return lsp.Hover{}
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if member, ok := nodes[i].(ast.MemberExpression); ok && member.Member.StartPos == tok.StartPos {
+			return doc.fieldHover(member)
+		}
+	}
+
+	decl, ok := doc.resolved.At(tok.StartPos)
+	if !ok {
+		return lsp.Hover{}
+	}
+	hover := hoverCode(fmt.Sprintf("(%s) %s", decl.Kind, decl.Name.Value))
+	if decl.Kind != resolve.KindVar {
+		return hover
+	}
+	if comment := doc.varDoc(decl.Stmt); comment != "" {
+		hover.Value += "\n\n" + comment
+	}
+	return hover
+}
+
+// fieldHover describes the field accessed or method called by member, with its
+// type and description if it is a member of a record.
+func (doc *document) fieldHover(member ast.MemberExpression) lsp.Hover {
+	hover := hoverCode(fmt.Sprintf("(field) %s", member.String()))
+	if member.Call {
+		hover = hoverCode(fmt.Sprintf("(method) %s", member.String()))
+	}
+	if member.Object == nil {
+		return hover
+	}
+	record, ok := doc.parser.TypeOf(member.Object)
+	if !ok {
+		return hover
+	}
+	if member.Call {
+		method, ok := object.RecordMethod(record.Type(), member.Member.Token.Literal)
+		if !ok {
+			return hover
+		}
+		fn := method.Doc()
+		hover = hoverCode(fmt.Sprintf("(method) %s.%s %s", member.Object.String(), fn.Signature, fn.Returns))
+		hover.Value += "\n\n" + fn.Desc
+		return hover
+	}
+	field, ok := doc.parser.Schemas().Field(record.Type(), member.Member.Token.Literal)
+	if !ok {
+		return hover
+	}
+	// the field is named as declared, as in completions and suggestions.
+	hover = hoverCode(fmt.Sprintf("(field) %s.%s %s", member.Object.String(), field.Name, field.Type))
+	if field.Desc != "" {
+		hover.Value += "\n\n" + field.Desc
+	}
+	return hover
+}
+
+// varDoc returns the doc comment of vs: the comments on the lines directly above
+// it, with the comment markers removed.
+func (doc *document) varDoc(vs ast.VarStatement) string {
+	comments := vs.Token.Leading()
+	if len(comments) == 0 {
+		// comments above top level statements are statements themselves.
+		for i, stmt := range doc.ast.Statements {
+			start, _ := stmt.Pos()
+			if start != vs.StartPos {
+				continue
+			}
+			line := vs.StartPos.Line
+			for j := i - 1; j >= 0; j-- {
+				c, ok := commentToken(doc.ast.Statements[j])
+				if !ok || c.StartPos.Line != line-1 {
+					break
+				}
+				comments = append([]token.Token{c}, comments...)
+				line--
+			}
+			break
+		}
+	}
+
+	lines := make([]string, 0, len(comments))
+	for _, c := range comments {
+		text := strings.TrimSpace(c.Literal)
+		text = strings.TrimPrefix(text, "//")
+		text = strings.TrimPrefix(text, "/*")
+		text = strings.TrimSuffix(text, "*/")
+		if text = strings.TrimSpace(text); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commentToken(stmt ast.Statement) (token.Token, bool) {
+	switch v := stmt.(type) {
+	case ast.LineCommentStatement:
+		return v.Token, true
+	case ast.BlockCommentStatement:
+		return v.Token, true
+	}
+	return token.Token{}, false
+}
+
+func hoverCode(code string) lsp.Hover {
+	return lsp.Hover{MarkupContent: lsp.MarkupContent{
+		Kind:  lsp.MarkupKindMarkdown,
+		Value: "```wflang\n" + code + "\n```",
+	}}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestHover(t *testing.T) {
+	input := `// regular hours
+// for the day
+var hours = 8;
+
+/* not a doc comment */
+
+var ot = (
+    // the threshold
+    var limit = 40;
+    hours - limit
+);
+sumTime(over day alias x, x.hours) - hours
++ countTime(over day alias t, where t.HASTIMES())`
+
+	tests := []struct {
+		name string
+		pos  lsp.Position
+		want string
+	}{
+		{"top level var", lsp.Position{Line: 11, Col: 40}, "```wflang\n(var) hours\n```\n\nregular hours\nfor the day"},
+		{"declaration", lsp.Position{Line: 2, Col: 5}, "```wflang\n(var) hours\n```\n\nregular hours\nfor the day"},
+		{"no doc comment", lsp.Position{Line: 6, Col: 4}, "```wflang\n(var) ot\n```"},
+		{"nested var", lsp.Position{Line: 9, Col: 13}, "```wflang\n(var) limit\n```\n\nthe threshold"},
+		{"alias", lsp.Position{Line: 11, Col: 26}, "```wflang\n(alias) x\n```"},
+		{"field", lsp.Position{Line: 11, Col: 29}, "```wflang\n(field) x.HOURS number\n```\n\nthe number of hours of the time record."},
+		{"method", lsp.Position{Line: 12, Col: 40}, "```wflang\n(method) t.hasTimes() boolean\n```\n\nReturns true if the time record has both a start and an end time."},
+	}
+
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if have := doc.hover(tt.pos).Value; have != tt.want {
+				t.Errorf("have %q, want %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeHover(t *testing.T) {
+	cat := &catalog.Catalog{}
+	cat.AddAttribute(catalog.Attribute{Name: "HOURLY_RATE", Type: types.T_NUMBER, Description: "Base rate of pay.", EffectiveDated: true})
+	cat.AddAttribute(catalog.Attribute{Name: "UNION", Type: types.T_STRING})
+	input := `employee_attribute(hourly_rate, day) + if(getAttributeCalculationDate("UNION", day) > {2024-01-01}, 1, 0) + employee_attribute(PENSION)`
+
+	tests := []struct {
+		name string
+		col  uint
+		want string
+	}{
+		{"ident", 22, "```wflang\n(attribute) HOURLY_RATE number\n```\n\nEffective dated: the value depends on the date it is read on.\n\nBase rate of pay."},
+		{"string", 72, "```wflang\n(attribute) UNION string\n```"},
+		{"unknown", 129, ""},
+	}
+
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16, parser.WithCatalog(cat))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if have := doc.hover(lsp.Position{Line: 0, Col: tt.col}).Value; have != tt.want {
+				t.Errorf("have %q, want %q", have, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/config"
+	"github.com/scatternoodle/wflang/wflang/format"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// formatOptions returns the format.Options for the client's formatting options.
+// The project configuration takes precedence, so that everyone working on the
+// project formats alike.
+func formatOptions(opts lsp.FormattingOptions, cfg config.Format) format.Options {
+	fOpts := cfg.Options()
+	if cfg.Indent == 0 && opts.TabSize > 0 {
+		fOpts.Indent = int(opts.TabSize)
+	}
+	return fOpts
+}
+
+// formatEdits returns the edits that format the statements of the document on the
+// lines spanned by rng, or the whole document if rng is nil. Documents with syntax
+// errors are left alone.
+func (doc *document) formatEdits(fOpts format.Options, rng *lsp.Range) ([]lsp.TextEdit, error) {
+	if doc.ast == nil || len(doc.parser.Errors()) > 0 {
+		return nil, format.ErrSyntax
+	}
+
+	if rng == nil {
+		text := format.Statements(doc.ast.Statements, fOpts)
+		if text == doc.text {
+			return nil, nil
+		}
+		return []lsp.TextEdit{{Range: lsp.Range{End: doc.conv.end()}, NewText: text}}, nil
+	}
+
+	start, err := doc.conv.fromLSP(rng.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := doc.conv.fromLSP(rng.End)
+	if err != nil {
+		return nil, err
+	}
+	var stmts []ast.Statement
+	for _, stmt := range doc.ast.Statements {
+		sStart, sEnd := stmt.Pos()
+		if sEnd.Line >= start.Line && sStart.Line <= end.Line {
+			stmts = append(stmts, stmt)
+		}
+	}
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+
+	// replace whole lines, so that the first statement is formatted from column 0.
+	first, _ := stmts[0].Pos()
+	_, last := stmts[len(stmts)-1].Pos()
+	return []lsp.TextEdit{{
+		Range:   doc.conv.toRange(token.Pos{Line: first.Line}, last),
+		NewText: strings.TrimSuffix(format.Statements(stmts, fOpts), "\n"),
+	}}, nil
+}
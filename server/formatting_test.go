@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/config"
+	"github.com/scatternoodle/wflang/wflang/format"
+)
+
+func TestFormatEdits(t *testing.T) {
+	rng := func(sl, sc, el, ec uint) *lsp.Range {
+		return &lsp.Range{Start: lsp.Position{Line: sl, Col: sc}, End: lsp.Position{Line: el, Col: ec}}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		rng   *lsp.Range
+		want  []lsp.TextEdit
+	}{
+		{
+			name:  "document",
+			input: "VAR a=1;\nmax(a,2)",
+			want:  []lsp.TextEdit{{Range: *rng(0, 0, 1, 8), NewText: "var a = 1;\nmax(a, 2)\n"}},
+		},
+		{
+			name:  "formatted document",
+			input: "var a = 1;\nmax(a, 2)\n",
+		},
+		{
+			name:  "range",
+			input: "var a=1;\n  var b=2;\nmax(a,b)",
+			rng:   rng(1, 4, 1, 5),
+			want:  []lsp.TextEdit{{Range: *rng(1, 0, 1, 10), NewText: "var b = 2;"}},
+		},
+		{
+			name:  "range between statements",
+			input: "var a=1;\n\nmax(a,2)",
+			rng:   rng(1, 0, 1, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: tt.input}, lsp.PositionEncodingUTF16)
+			have, err := doc.formatEdits(formatOptions(lsp.FormattingOptions{TabSize: 4, InsertSpaces: true}, config.Format{}), tt.rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(have) != len(tt.want) {
+				t.Fatalf("edits: have %+v, want %+v", have, tt.want)
+			}
+			for i := range have {
+				if have[i] != tt.want[i] {
+					t.Errorf("edit %d: have %+v, want %+v", i, have[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: "max(1,"}, lsp.PositionEncodingUTF16)
+	if _, err := doc.formatEdits(format.DefaultOptions(), nil); err == nil {
+		t.Error("expected an error formatting a document with syntax errors")
+	}
+}
@@ -5,12 +5,10 @@ import (
 	"io"
 	"log/slog"
 	"os"
-	"slices"
 
 	"github.com/scatternoodle/wflang/internal/jrpc2"
 	"github.com/scatternoodle/wflang/internal/lsp"
 	"github.com/scatternoodle/wflang/wflang"
-	"github.com/scatternoodle/wflang/wflang/token"
 )
 
 // handlerFunc takes an io.Writer and a byte slice containing the contents of an
@@ -28,6 +26,13 @@ func (srv *Server) handleInitializeRequest(w io.Writer, c []byte, id *int) {
 		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error())
 	}
 	send(w, srv.initializeResponse(id))
+
+	if r.Params.RootURI != nil {
+		srv.setRoot(*r.Params.RootURI)
+	}
+	if err := srv.loadConfig(); err != nil {
+		showMessage(w, lsp.Error, fmt.Sprintf("wflang: %s", err))
+	}
 }
 
 func (srv *Server) handleInitializedNotification(w io.Writer, _ []byte, _ *int) {
@@ -74,7 +79,7 @@ func (srv *Server) handleDocDidOpenNotification(w io.Writer, c []byte, id *int)
 	if !handleParseContent(&r, w, c, id) {
 		return
 	}
-	srv.updateDocument(r.Params.TextDocument)
+	publishDiagnostics(w, srv.updateDocument(r.Params.TextDocument))
 }
 
 func (srv *Server) handleDocDidChangeNotification(w io.Writer, c []byte, id *int) {
@@ -83,18 +88,35 @@ func (srv *Server) handleDocDidChangeNotification(w io.Writer, c []byte, id *int
 		return
 	}
 
-	// we will update document with the last item in the changes array - we're only
-	// only interested in the latest state of the document as we do not support
-	// incremental updates.
-	changes := r.Params.ContentChanges
-	lastChange := changes[len(changes)-1]
+	uri, version := r.Params.TextDocument.URI, r.Params.TextDocument.Version
+	doc, ok := srv.documents[uri]
+	if !ok {
+		slog.Error("change notification for unknown document", "uri", uri)
+		return
+	}
+	// versions strictly increase with each change, so anything else is stale or
+	// out of order and would corrupt the stored text if applied.
+	if version <= doc.version {
+		slog.Warn("dropping out-of-order change", "uri", uri, "version", version, "current", doc.version)
+		return
+	}
 
-	srv.updateDocument(
-		lsp.TextDocumentItem{
-			URI:     r.Params.TextDocument.URI,
-			Version: r.Params.TextDocument.Version,
-			Text:    lastChange.Text,
-		})
+	text, err := applyChanges(doc.text, r.Params.ContentChanges, srv.encoding)
+	if err != nil {
+		slog.Error("unable to apply document changes", "uri", uri, "version", version, "error", err)
+		return
+	}
+	doc = srv.updateDocument(lsp.TextDocumentItem{URI: uri, Version: version, Text: text})
+	publishDiagnostics(w, doc)
+}
+
+func (srv *Server) handleDocDidCloseNotification(w io.Writer, c []byte, id *int) {
+	var r lsp.NotificationDidClose
+	if !handleParseContent(&r, w, c, id) {
+		return
+	}
+	srv.closeDocument(r.Params.TextDocument.URI)
+	clearDiagnostics(w, r.Params.TextDocument.URI)
 }
 
 func (srv *Server) handleDocDidSaveNotification(w io.Writer, c []byte, id *int) {
@@ -106,10 +128,14 @@ func (srv *Server) handleSemanticTokensFullRequest(w io.Writer, c []byte, id *in
 	if !handleAssertID(w, id) || !handleParseContent(&r, w, c, id) {
 		return
 	}
+	doc, ok := srv.handleGetDocument(w, id, r.Params.TextDocument.URI)
+	if !ok {
+		return
+	}
 	send(w, &lsp.SemanticTokensResponse{
 		Response: jrpc2.NewResponse(id, nil),
 		Result: lsp.SemanticTokensResult{
-			Data: srv.semTokens,
+			Data: doc.semTokens,
 		},
 	})
 }
@@ -119,10 +145,14 @@ func (srv *Server) handleHoverRequest(w io.Writer, c []byte, id *int) {
 	if !handleAssertID(w, id) || !handleParseContent(&r, w, c, id) {
 		return
 	}
+	doc, ok := srv.handleGetDocument(w, id, r.URI)
+	if !ok {
+		return
+	}
 
 	send(w, lsp.HoverResponse{
 		Response: jrpc2.NewResponse(id, nil),
-		Hover:    srv.hover(r.Position),
+		Hover:    doc.hover(r.Position),
 	})
 }
 
@@ -131,16 +161,14 @@ func (srv *Server) handleDocumentSymbolsRequest(w io.Writer, c []byte, id *int)
 	if !handleAssertID(w, id) || !handleParseContent(&r, w, c, id) {
 		return
 	}
-
-	res := []lsp.DocumentSymbol{}
-	if srv.symbols != nil {
-		for _, sym := range srv.symbols {
-			res = append(res, sym)
-		}
+	doc, ok := srv.handleGetDocument(w, id, r.Params.TextDocument.URI)
+	if !ok {
+		return
 	}
+
 	send(w, lsp.DocumentSymbolResponse{
 		Response: jrpc2.NewResponse(id, nil),
-		Result:   res,
+		Result:   doc.symbols,
 	})
 }
 
@@ -149,26 +177,63 @@ func (srv *Server) handleGotoDefinitionRequest(w io.Writer, c []byte, id *int) {
 	if !handleAssertID(w, id) || !handleParseContent(&reqObj, w, c, id) {
 		return
 	}
+	doc, ok := srv.handleGetDocument(w, id, reqObj.Params.URI)
+	if !ok {
+		return
+	}
 
 	res := lsp.GotoDefinitionResponse{Response: jrpc2.NewResponse(id, nil)}
-	sym, ok := srv.symbolFromPos(reqObj.Params.Position)
-	if ok {
+	if _, decl, ok := doc.declAtPos(reqObj.Params.Position); ok {
 		res.Result = &lsp.Location{
 			URI:   reqObj.Params.URI,
-			Range: sym.SelectionRange,
+			Range: doc.conv.toRange(decl.Name.Pos()),
 		}
 	}
 	send(w, res)
 }
 
+func (srv *Server) handleReferencesRequest(w io.Writer, c []byte, id *int) {
+	var req lsp.ReferencesRequest
+	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
+		return
+	}
+	doc, ok := srv.handleGetDocument(w, id, req.Params.URI)
+	if !ok {
+		return
+	}
+	send(w, lsp.ReferencesResponse{
+		Response: jrpc2.NewResponse(id, nil),
+		Result:   doc.references(req.Params.Position, req.Params.Context.IncludeDeclaration),
+	})
+}
+
+func (srv *Server) handleDocumentHighlightRequest(w io.Writer, c []byte, id *int) {
+	var req lsp.DocumentHighlightRequest
+	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
+		return
+	}
+	doc, ok := srv.handleGetDocument(w, id, req.Params.URI)
+	if !ok {
+		return
+	}
+	send(w, lsp.DocumentHighlightResponse{
+		Response: jrpc2.NewResponse(id, nil),
+		Result:   doc.highlights(req.Params.Position),
+	})
+}
+
 func (srv *Server) handleCompletionRequest(w io.Writer, c []byte, id *int) {
 	var req lsp.CompletionRequest
 	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
 		return
 	}
+	doc, ok := srv.handleGetDocument(w, id, req.Params.URI)
+	if !ok {
+		return
+	}
 	send(w, lsp.CompletionResponse{
 		Response: jrpc2.NewResponse(id, nil),
-		Result:   srv.completions(req.Params.Position),
+		Result:   doc.completions(req.Params.Position),
 	})
 }
 
@@ -177,47 +242,40 @@ func (srv *Server) handleRenameRequest(w io.Writer, c []byte, id *int) {
 	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
 		return
 	}
-
-	_, reqTok, ok := srv.getTokenAtPos(req.Position)
+	doc, ok := srv.handleGetDocument(w, id, req.URI)
 	if !ok {
-		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, fmt.Sprintf("no token found at position %+v", req.Position), nil)
+		return
 	}
-	if reqTok.Type != token.T_IDENT {
-		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, fmt.Sprintf("invalid token type for rename %s", reqTok.Type), nil)
+
+	edits, err := doc.renameEdits(req.Position, req.NewName)
+	if err != nil {
+		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error(), nil)
+		return
 	}
+	send(w, lsp.RenameResponse{
+		Response: jrpc2.NewResponse(id, nil),
+		Result:   &lsp.WorkspaceEdit{Changes: map[string][]lsp.TextEdit{doc.uri: edits}},
+	})
+}
 
-	vars := srv.parser.Vars()
-	varNames := make([]string, 0, len(vars))
-	for _, varObj := range vars {
-		varNames = append(varNames, varObj.Name)
+func (srv *Server) handlePrepareRenameRequest(w io.Writer, c []byte, id *int) {
+	var req lsp.PrepareRenameRequest
+	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
+		return
 	}
-	if !slices.Contains(varNames, reqTok.Literal) {
-		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, fmt.Sprintf("token %s is not a variable", reqTok.Literal), nil)
+	doc, ok := srv.handleGetDocument(w, id, req.Params.URI)
+	if !ok {
+		return
 	}
 
-	edits := []lsp.TextEdit{}
-	for _, tok := range srv.parser.Tokens() {
-		if tok.Literal != reqTok.Literal {
-			continue
-		}
-		edits = append(edits, lsp.TextEdit{
-			Range: lsp.Range{
-				Start: lsp.Position{
-					Line: tok.StartPos.Line,
-					Col:  tok.StartPos.Col,
-				},
-				End: lsp.Position{
-					Line: tok.EndPos.Line,
-					Col:  tok.EndPos.Col + 1,
-				},
-			},
-			NewText: req.NewName,
-		})
-	}
-	wsEdit := &lsp.WorkspaceEdit{Changes: map[string][]lsp.TextEdit{srv.uri: edits}}
-	send(w, lsp.RenameResponse{
+	rng, err := doc.prepareRename(req.Params.Position)
+	if err != nil {
+		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error(), nil)
+		return
+	}
+	send(w, lsp.PrepareRenameResponse{
 		Response: jrpc2.NewResponse(id, nil),
-		Result:   wsEdit,
+		Result:   &rng,
 	})
 }
 
@@ -226,8 +284,17 @@ func (srv *Server) handleSignatureHelpRequest(w io.Writer, c []byte, id *int) {
 	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
 		return
 	}
+	doc, ok := srv.handleGetDocument(w, id, req.URI)
+	if !ok {
+		return
+	}
 
-	info, activeParam, err := wflang.SignatureHelp(srv.ast, token.Pos(req.Position))
+	pos, err := doc.conv.fromLSP(req.Position)
+	if err != nil {
+		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error())
+		return
+	}
+	info, activeParam, err := wflang.SignatureHelp(doc.ast, pos)
 	if err != nil {
 		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error())
 		return
@@ -247,3 +314,37 @@ func (srv *Server) handleSignatureHelpRequest(w io.Writer, c []byte, id *int) {
 
 	send(w, resp)
 }
+
+func (srv *Server) handleFormattingRequest(w io.Writer, c []byte, id *int) {
+	var req lsp.DocumentFormattingRequest
+	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
+		return
+	}
+	srv.respondFormatting(w, id, req.TextDocument.URI, req.Options, nil)
+}
+
+func (srv *Server) handleRangeFormattingRequest(w io.Writer, c []byte, id *int) {
+	var req lsp.DocumentRangeFormattingRequest
+	if !handleAssertID(w, id) || !handleParseContent(&req, w, c, id) {
+		return
+	}
+	srv.respondFormatting(w, id, req.TextDocument.URI, req.Options, &req.Range)
+}
+
+// respondFormatting responds with the edits that format the document, or its
+// range rng if not nil.
+func (srv *Server) respondFormatting(w io.Writer, id *int, uri string, opts lsp.FormattingOptions, rng *lsp.Range) {
+	doc, ok := srv.handleGetDocument(w, id, uri)
+	if !ok {
+		return
+	}
+	edits, err := doc.formatEdits(formatOptions(opts, srv.config.Format), rng)
+	if err != nil {
+		respondError(w, id, lsp.ERRCODE_REQUEST_FAILED, err.Error())
+		return
+	}
+	send(w, lsp.DocumentFormattingResponse{
+		Response: jrpc2.NewResponse(id, nil),
+		Result:   edits,
+	})
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// supportedEncodings returns the position encodings the server can use.
+func supportedEncodings() []lsp.PositionEncodingKind {
+	return []lsp.PositionEncodingKind{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF16}
+}
+
+// negotiateEncoding returns the first of the client's preferred position encodings
+// that the server supports, or utf-16 if there is none.
+func negotiateEncoding(caps lsp.ClientCapabilities) lsp.PositionEncodingKind {
+	if caps.General == nil {
+		return lsp.PositionEncodingUTF16
+	}
+	for _, enc := range caps.General.PositionEncodings {
+		if slices.Contains(supportedEncodings(), enc) {
+			return enc
+		}
+	}
+	return lsp.PositionEncodingUTF16
+}
+
+// posConverter converts between token.Pos, whose columns count bytes, and
+// lsp.Position, whose characters count units of the negotiated encoding. All
+// conversions between the two must go through a posConverter.
+type posConverter struct {
+	text       string
+	lineStarts []int // byte offset at which each line starts.
+	encoding   lsp.PositionEncodingKind
+}
+
+func newPosConverter(text string, encoding lsp.PositionEncodingKind) *posConverter {
+	c := &posConverter{text: text, lineStarts: []int{0}, encoding: encoding}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			c.lineStarts = append(c.lineStarts, i+1)
+		}
+	}
+	return c
+}
+
+// line returns the text of line n, excluding any line ending.
+func (c *posConverter) line(n uint) (string, bool) {
+	if int(n) >= len(c.lineStarts) {
+		return "", false
+	}
+	end := len(c.text)
+	if int(n)+1 < len(c.lineStarts) {
+		end = c.lineStarts[n+1] - 1
+	}
+	line := c.text[c.lineStarts[n]:end]
+	return strings.TrimSuffix(line, "\r"), true
+}
+
+// units returns the length of s in units of the converter's encoding.
+func (c *posConverter) units(s string) uint {
+	if c.encoding == lsp.PositionEncodingUTF8 {
+		return uint(len(s))
+	}
+	var n uint
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			n++ // encoded as a surrogate pair
+		}
+	}
+	return n
+}
+
+// toLSP converts pos to an lsp.Position. Columns beyond the end of a line refer to
+// the end of that line.
+func (c *posConverter) toLSP(pos token.Pos) lsp.Position {
+	line, ok := c.line(pos.Line)
+	if !ok {
+		return lsp.Position{Line: pos.Line}
+	}
+	col := min(int(pos.Col), len(line))
+	return lsp.Position{Line: pos.Line, Col: c.units(line[:col])}
+}
+
+// fromLSP converts pos to a token.Pos. Characters beyond the end of a line refer to
+// the end of that line, and a character within a multi-unit sequence refers to the
+// start of that sequence.
+func (c *posConverter) fromLSP(pos lsp.Position) (token.Pos, error) {
+	line, ok := c.line(pos.Line)
+	if !ok {
+		return token.Pos{}, fmt.Errorf("line %d out of range, document has %d lines", pos.Line, len(c.lineStarts))
+	}
+	var units uint
+	for i, r := range line {
+		next := units + c.units(string(r))
+		if next > pos.Col {
+			return token.Pos{Line: pos.Line, Col: uint(i)}, nil
+		}
+		units = next
+	}
+	return token.Pos{Line: pos.Line, Col: uint(len(line))}, nil
+}
+
+// offset returns the byte offset of pos within the text.
+func (c *posConverter) offset(pos lsp.Position) (int, error) {
+	tPos, err := c.fromLSP(pos)
+	if err != nil {
+		return 0, err
+	}
+	return c.lineStarts[tPos.Line] + int(tPos.Col), nil
+}
+
+// toRange converts the inclusive start and end positions of a token or node to an
+// lsp.Range, the end of which is exclusive.
+func (c *posConverter) toRange(start, end token.Pos) lsp.Range {
+	return lsp.Range{Start: c.toLSP(start), End: c.toLSP(end.Right(1))}
+}
+
+// end returns the position of the end of the text.
+func (c *posConverter) end() lsp.Position {
+	last := uint(len(c.lineStarts) - 1)
+	line, _ := c.line(last)
+	return lsp.Position{Line: last, Col: c.units(line)}
+}
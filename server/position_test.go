@@ -0,0 +1,89 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+func TestPosConverter(t *testing.T) {
+	// line 0: "é" is 2 bytes / 1 utf-16 unit, "😀" is 4 bytes / 2 utf-16 units.
+	text := "// é😀 x\r\nx + y"
+
+	tests := []struct {
+		name     string
+		encoding lsp.PositionEncodingKind
+		tPos     token.Pos
+		lspPos   lsp.Position
+	}{
+		{"utf-16 line start", lsp.PositionEncodingUTF16, token.Pos{Line: 0, Col: 0}, lsp.Position{Line: 0, Col: 0}},
+		{"utf-16 after 2-byte char", lsp.PositionEncodingUTF16, token.Pos{Line: 0, Col: 5}, lsp.Position{Line: 0, Col: 4}},
+		{"utf-16 after surrogate pair", lsp.PositionEncodingUTF16, token.Pos{Line: 0, Col: 9}, lsp.Position{Line: 0, Col: 6}},
+		{"utf-16 line end", lsp.PositionEncodingUTF16, token.Pos{Line: 0, Col: 11}, lsp.Position{Line: 0, Col: 8}},
+		{"utf-16 next line", lsp.PositionEncodingUTF16, token.Pos{Line: 1, Col: 4}, lsp.Position{Line: 1, Col: 4}},
+		{"utf-8 after surrogate pair", lsp.PositionEncodingUTF8, token.Pos{Line: 0, Col: 9}, lsp.Position{Line: 0, Col: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := newPosConverter(text, tt.encoding)
+			if have := conv.toLSP(tt.tPos); have != tt.lspPos {
+				t.Errorf("toLSP: have %+v, want %+v", have, tt.lspPos)
+			}
+			have, err := conv.fromLSP(tt.lspPos)
+			if err != nil {
+				t.Fatalf("fromLSP: %v", err)
+			}
+			if have != tt.tPos {
+				t.Errorf("fromLSP: have %+v, want %+v", have, tt.tPos)
+			}
+		})
+	}
+
+	t.Run("clamped", func(t *testing.T) {
+		conv := newPosConverter(text, lsp.PositionEncodingUTF16)
+		if have := conv.toLSP(token.Pos{Line: 0, Col: 99}); have != (lsp.Position{Line: 0, Col: 8}) {
+			t.Errorf("toLSP: have %+v, want end of line", have)
+		}
+		have, _ := conv.fromLSP(lsp.Position{Line: 0, Col: 5}) // within surrogate pair
+		if want := (token.Pos{Line: 0, Col: 5}); have != want {
+			t.Errorf("fromLSP: have %+v, want %+v", have, want)
+		}
+		if _, err := conv.fromLSP(lsp.Position{Line: 2}); err == nil {
+			t.Error("fromLSP: want error for line out of range")
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		conv := newPosConverter(text, lsp.PositionEncodingUTF16)
+		want := lsp.Range{Start: lsp.Position{Line: 0, Col: 3}, End: lsp.Position{Line: 0, Col: 6}}
+		if have := conv.toRange(token.Pos{Line: 0, Col: 3}, token.Pos{Line: 0, Col: 8}); have != want {
+			t.Errorf("toRange: have %+v, want %+v", have, want)
+		}
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		caps lsp.ClientCapabilities
+		want lsp.PositionEncodingKind
+	}{
+		{"none", lsp.ClientCapabilities{}, lsp.PositionEncodingUTF16},
+		{"utf-8 preferred", lsp.ClientCapabilities{General: &lsp.GeneralClientCapabilities{
+			PositionEncodings: []lsp.PositionEncodingKind{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF16},
+		}}, lsp.PositionEncodingUTF8},
+		{"unsupported skipped", lsp.ClientCapabilities{General: &lsp.GeneralClientCapabilities{
+			PositionEncodings: []lsp.PositionEncodingKind{lsp.PositionEncodingUTF32, lsp.PositionEncodingUTF16},
+		}}, lsp.PositionEncodingUTF16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if have := negotiateEncoding(tt.caps); have != tt.want {
+				t.Fatalf("have %s, want %s", have, tt.want)
+			}
+		})
+	}
+}
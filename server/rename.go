@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// renameTarget returns the ident at pos and the declaration of the var or alias
+// that it declares or refers to, as found by declAtPos.
+func (doc *document) renameTarget(pos lsp.Position) (token.Token, *resolve.Decl, error) {
+	tok, decl, ok := doc.declAtPos(pos)
+	switch {
+	case ok:
+		return tok, decl, nil
+	case tok.Type == "":
+		return token.Token{}, nil, fmt.Errorf("no token found at position %+v", pos)
+	case tok.Type != token.T_IDENT:
+		return token.Token{}, nil, fmt.Errorf("cannot rename %s, only vars and aliases can be renamed", tok.Type)
+	}
+	return token.Token{}, nil, fmt.Errorf("cannot rename %s, only vars and aliases can be renamed", tok.Literal)
+}
+
+// prepareRename returns the range of the name of the var or alias at pos.
+func (doc *document) prepareRename(pos lsp.Position) (lsp.Range, error) {
+	tok, _, err := doc.renameTarget(pos)
+	if err != nil {
+		return lsp.Range{}, err
+	}
+	return doc.conv.toRange(tok.StartPos, tok.EndPos), nil
+}
+
+// renameEdits returns the edits that rename the var or alias at pos, and every
+// reference to it, to newName. It returns an error if newName is not a valid
+// identifier, or if renaming would change what another identifier refers to.
+func (doc *document) renameEdits(pos lsp.Position, newName string) ([]lsp.TextEdit, error) {
+	_, decl, err := doc.renameTarget(pos)
+	if err != nil {
+		return nil, err
+	}
+	if !parser.IsIdentLiteral(newName) {
+		return nil, fmt.Errorf("%q is not a valid identifier", newName)
+	}
+	if parser.IsReserved(newName) {
+		return nil, fmt.Errorf("%s is a reserved word, and cannot be used as an identifier", newName)
+	}
+	if ident, ok := doc.resolved.Conflict(decl, newName); ok {
+		start, _ := ident.Pos()
+		return nil, fmt.Errorf("renaming %s to %s would conflict with %s at %d:%d",
+			decl.Name.Value, newName, ident.Value, start.Line+1, start.Col+1)
+	}
+
+	edits := []lsp.TextEdit{}
+	for _, ident := range append([]ast.Ident{decl.Name}, doc.resolved.RefsTo(decl)...) {
+		edits = append(edits, lsp.TextEdit{
+			Range:   doc.conv.toRange(ident.Pos()),
+			NewText: newName,
+		})
+	}
+	return edits, nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/internal/lsp"
+)
+
+func TestPrepareRename(t *testing.T) {
+	input := `var ab = 1; sumTime(over day alias t, t.HOURS) + ab + "ab"`
+	doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+
+	tests := []struct {
+		name      string
+		col       uint
+		wantStart uint
+		wantEnd   uint
+		wantErr   bool
+	}{
+		{"declaration", 5, 4, 6, false},
+		{"reference", 50, 49, 51, false},
+		{"end of reference", 51, 49, 51, false},
+		{"end of declaration", 6, 4, 6, false},
+		{"alias", 38, 38, 39, false},
+		{"field", 40, 0, 0, true},
+		{"builtin", 13, 0, 0, true},
+		{"string", 55, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng, err := doc.prepareRename(lsp.Position{Line: 0, Col: tt.col})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("have error %v, want error %t", err, tt.wantErr)
+			}
+			if err == nil && (rng.Start.Col != tt.wantStart || rng.End.Col != tt.wantEnd) {
+				t.Errorf("have range %d-%d, want %d-%d", rng.Start.Col, rng.End.Col, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRenameEdits(t *testing.T) {
+	input := "var x = 1;\n(var x = 2; x) + x + sumTime(over day alias x, x.HOURS) + \"x\""
+
+	tests := []struct {
+		name      string
+		pos       lsp.Position
+		newName   string
+		wantEdits []string // "line:col" of each edit.
+		wantErr   string
+	}{
+		{"outer", lsp.Position{Line: 0, Col: 4}, "y", []string{"0:4", "1:17"}, ""},
+		{"inner", lsp.Position{Line: 1, Col: 12}, "y", []string{"1:5", "1:12"}, ""},
+		{"alias", lsp.Position{Line: 1, Col: 47}, "y", []string{"1:44", "1:47"}, ""},
+		{"invalid", lsp.Position{Line: 0, Col: 4}, "1y", nil, "not a valid identifier"},
+		{"keyword", lsp.Position{Line: 0, Col: 4}, "VAR", nil, "reserved word"},
+		{"builtin", lsp.Position{Line: 0, Col: 4}, "sumTime", nil, "reserved word"},
+		{"field", lsp.Position{Line: 1, Col: 49}, "y", nil, "only vars and aliases"},
+		{"global", lsp.Position{Line: 0, Col: 4}, "day", nil, "conflict with day at 2:35"},
+		{"end of ident", lsp.Position{Line: 1, Col: 18}, "y", []string{"0:4", "1:17"}, ""},
+		{"field name", lsp.Position{Line: 1, Col: 44}, "hours", nil, "conflict with x at 2:48"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newDocument(lsp.TextDocumentItem{URI: "file:///test.wflang", Text: input}, lsp.PositionEncodingUTF16)
+			edits, err := doc.renameEdits(tt.pos, tt.newName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("have error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []string
+			for _, e := range edits {
+				if e.NewText != tt.newName {
+					t.Errorf("have new text %s, want %s", e.NewText, tt.newName)
+				}
+				have = append(have, fmt.Sprintf("%d:%d", e.Range.Start.Line, e.Range.Start.Col))
+			}
+			if strings.Join(have, " ") != strings.Join(tt.wantEdits, " ") {
+				t.Errorf("have edits %v, want %v", have, tt.wantEdits)
+			}
+		})
+	}
+}
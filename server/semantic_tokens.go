@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"slices"
 
+	"github.com/scatternoodle/wflang/wflang/ast"
+
 	"github.com/scatternoodle/wflang/wflang/token"
 )
 
@@ -111,22 +113,48 @@ func tokenMap() map[token.Type]string {
 	}
 }
 
-func newTokenEncoder(tokens []token.Token) *tokenEncoder {
+// newTokenEncoder encodes the given tokens, with positions and lengths converted by
+// conv. If root is non-nil, it is used to refine the token types, e.g. telling
+// member fields apart from variables.
+func newTokenEncoder(tokens []token.Token, root *ast.AST, conv *posConverter) *tokenEncoder {
 	slog.Debug("newTokenEncoder called with", "tokens", tokens)
 	e := &tokenEncoder{
-		types:   tokenTypes(),
-		typeMap: tokenMap(),
+		types:     tokenTypes(),
+		typeMap:   tokenMap(),
+		overrides: tokenOverrides(root),
+		conv:      conv,
 	}
 	e.encode(tokens)
 	return e
 }
 
+// tokenOverrides returns the semantic types of tokens whose type depends on their
+// place in the AST rather than on the token type alone, keyed by StartPos.
+func tokenOverrides(root *ast.AST) map[token.Pos]string {
+	overrides := map[token.Pos]string{}
+	if root == nil {
+		return overrides
+	}
+	ast.Inspect(root, func(n ast.Node) bool {
+		if member, ok := n.(ast.MemberExpression); ok {
+			overrides[member.Member.StartPos] = semProperty
+			if member.Call {
+				overrides[member.Member.StartPos] = semMethod
+			}
+		}
+		return true
+	})
+	return overrides
+}
+
 // tokenEncoder stores encoded LSP semantic tokens, as well as the type legend for
 // the language server.
 type tokenEncoder struct {
 	types     []string
 	semTokens []uint
 	typeMap   map[token.Type]string
+	overrides map[token.Pos]string
+	conv      *posConverter
 	// modifiers []string - not currently handled.
 }
 
@@ -139,9 +167,11 @@ func (t *tokenEncoder) encode(parserTokens []token.Token) {
 	semTok := make([]uint, 5)
 
 	for _, token := range parserTokens {
-		typeStr, ok := t.typeMap[token.Type]
+		typeStr, ok := t.overrides[token.StartPos]
 		if !ok {
-			continue
+			if typeStr, ok = t.typeMap[token.Type]; !ok {
+				continue
+			}
 		}
 
 		idx := slices.Index(t.types, typeStr)
@@ -153,8 +183,10 @@ func (t *tokenEncoder) encode(parserTokens []token.Token) {
 			continue
 		}
 
-		crrLine = uint(token.StartPos.Line)
-		crrCol = uint(token.StartPos.Col)
+		start := t.conv.toLSP(token.StartPos)
+		end := t.conv.toLSP(token.StartPos.Right(token.Len))
+		crrLine = start.Line
+		crrCol = start.Col
 		deltaLine = crrLine - prvLine
 		deltaCol = crrCol
 		if crrLine == prvLine {
@@ -165,7 +197,7 @@ func (t *tokenEncoder) encode(parserTokens []token.Token) {
 
 		semTok[0] = deltaLine
 		semTok[1] = deltaCol
-		semTok[2] = uint(token.Len)
+		semTok[2] = end.Col - start.Col
 		semTok[3] = uint(idx)
 		semTok[4] = 0 // not currently handling modifier bitmasks
 
@@ -5,6 +5,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/scatternoodle/wflang/internal/lsp"
 	"github.com/scatternoodle/wflang/wflang/lexer"
 	"github.com/scatternoodle/wflang/wflang/parser"
 )
@@ -37,6 +38,33 @@ func TestEncode(t *testing.T) {
 				0, 2, 15, uint(slices.Index(tokTypes, semString)), 0, // "hello, world!"
 			},
 		},
+		{
+			name:  "member access",
+			input: `x.hours`,
+			want: []uint{
+				0, 0, 1, uint(slices.Index(tokTypes, semVariable)), 0, // x
+				0, 2, 5, uint(slices.Index(tokTypes, semProperty)), 0, // hours
+			},
+		},
+		{
+			name:  "method call",
+			input: `x.hasTimes()`,
+			want: []uint{
+				0, 0, 1, uint(slices.Index(tokTypes, semVariable)), 0, // x
+				0, 2, 8, uint(slices.Index(tokTypes, semMethod)), 0, // hasTimes
+			},
+		},
+		{
+			name:  "utf-16 columns",
+			input: `var s = "héllo"; s`,
+			want: []uint{
+				0, 0, 3, uint(slices.Index(tokTypes, semKeyword)), 0, // var
+				0, 4, 1, uint(slices.Index(tokTypes, semVariable)), 0, // s
+				0, 2, 1, uint(slices.Index(tokTypes, semOperator)), 0, // =
+				0, 2, 7, uint(slices.Index(tokTypes, semString)), 0, // "héllo"
+				0, 9, 1, uint(slices.Index(tokTypes, semVariable)), 0, // s
+			},
+		},
 		{
 			name:  "multiline blockcomment",
 			input: "/*1\n2*/",
@@ -50,7 +78,8 @@ func TestEncode(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parser := parser.New(lexer.New(tt.input))
-			encoder := newTokenEncoder(parser.Tokens())
+			AST, _ := parser.AST()
+			encoder := newTokenEncoder(parser.Tokens(), AST, newPosConverter(tt.input, lsp.PositionEncodingUTF16))
 
 			if !reflect.DeepEqual(encoder.semTokens, tt.want) {
 				t.Fatalf("have %v, want %v", encoder.semTokens, tt.want)
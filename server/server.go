@@ -10,8 +10,8 @@ import (
 
 	"github.com/scatternoodle/wflang/internal/jrpc2"
 	"github.com/scatternoodle/wflang/internal/lsp"
-	"github.com/scatternoodle/wflang/wflang/ast"
-	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/config"
 	"github.com/scatternoodle/wflang/wflang/token"
 )
 
@@ -24,11 +24,10 @@ func New(name, version *string, dbg bool) *Server {
 		name:         name,
 		version:      version,
 		trace:        lsp.TraceOff,
-		uri:          "",
 		initialized:  false,
 		capabilities: serverCapabilities(),
-		parser:       nil,
-		ast:          nil,
+		documents:    map[string]*document{},
+		encoding:     lsp.PositionEncodingUTF16,
 	}
 
 	srv.handlers = map[string]handlerFunc{
@@ -37,40 +36,46 @@ func New(name, version *string, dbg bool) *Server {
 		lsp.MethodDocDidOpen:         srv.handleDocDidOpenNotification,
 		lsp.MethodDocDidChange:       srv.handleDocDidChangeNotification,
 		lsp.MethodDocDidSave:         srv.handleDocDidSaveNotification,
+		lsp.MethodDocDidClose:        srv.handleDocDidCloseNotification,
 		lsp.MethodSemanticTokensFull: srv.handleSemanticTokensFullRequest,
 		lsp.MethodHover:              srv.handleHoverRequest,
 		lsp.MethodShutdown:           srv.handleShutdownRequest,
 		lsp.MethodExit:               srv.handleExitNotification,
 		lsp.MethodDocumentSymbols:    srv.handleDocumentSymbolsRequest,
 		lsp.MethodDefinition:         srv.handleGotoDefinitionRequest,
+		lsp.MethodReferences:         srv.handleReferencesRequest,
+		lsp.MethodDocumentHighlight:  srv.handleDocumentHighlightRequest,
 		lsp.MethodCompletion:         srv.handleCompletionRequest,
 		lsp.MethodRename:             srv.handleRenameRequest,
+		lsp.MethodPrepareRename:      srv.handlePrepareRenameRequest,
 		lsp.MethodSignatureHelp:      srv.handleSignatureHelpRequest,
+		lsp.MethodFormatting:         srv.handleFormattingRequest,
+		lsp.MethodRangeFormatting:    srv.handleRangeFormattingRequest,
 		lsp.MethodSetTrace:           srv.handleSetTraceNotification,
+		lsp.MethodDidChangeConfig:    srv.handleDidChangeConfigNotification,
+		lsp.MethodDidChangeWatched:   srv.handleDidChangeWatchedNotification,
 	}
 	return srv
 }
 
 type Server struct {
-	name    *string
-	version *string
-	trace   lsp.TraceValue
-	// for now, server only handles a single document - this likely will need to turn into a map[string]*parser.Parser at some point
-	uri          string
+	name         *string
+	version      *string
+	trace        lsp.TraceValue
 	capabilities lsp.ServerCapabilities
-	initialized  bool // before this is set true, we only accept requests with initialize method
-	exiting      bool // set after an shutdown request is received, awaiting exit request
-	parser       *parser.Parser
-	ast          *ast.AST
+	initialized  bool                     // before this is set true, we only accept requests with initialize method
+	exiting      bool                     // set after an shutdown request is received, awaiting exit request
+	encoding     lsp.PositionEncodingKind // negotiated on initialize, applies to all documents
 	handlers     map[string]handlerFunc
-	symbols      map[string]lsp.DocumentSymbol
-
-	*tokenEncoder
+	documents    map[string]*document // open documents, keyed by URI.
+	config       config.Config        // the project configuration, loaded from configPath.
+	configPath   string               // the configuration file in the workspace root, empty without a root.
+	catalog      *catalog.Catalog     // loaded from the catalogue files of config.
 }
 
 func serverCapabilities() lsp.ServerCapabilities {
 	return lsp.ServerCapabilities{
-		TextDocumentSync: lsp.SyncFull,
+		TextDocumentSync: lsp.SyncIncremental,
 		SemanticTokensProvider: lsp.SemanticTokensOptions{
 			Legend: lsp.TokenTypesLegend{
 				TokenTypes:     tokenTypes(),
@@ -82,19 +87,25 @@ func serverCapabilities() lsp.ServerCapabilities {
 		HoverProvider:          true,
 		DocumentSymbolProvider: true,
 		DefinitionProvider:     true,
+		ReferencesProvider:     true,
 		CompletionProvider: lsp.CompletionOptions{
 			CompletionItem: &lsp.CompletionItemOptions{LabelDetailsSupport: true},
 			// TODO we'll want trigger on at least ',' once methods implemented
 		},
-		RenameProvider: true,
+		RenameProvider: &lsp.RenameOptions{PrepareProvider: true},
 		SignatureHelpProvider: &lsp.SignatureHelpOptions{
 			TriggerChars:   []string{"("},
 			RetriggerChars: nil,
 		},
+		DocumentFormattingProvider:      true,
+		DocumentRangeFormattingProvider: true,
+		DocumentHighlightProvider:       true,
 	}
 }
 
 func (srv *Server) setInit(req lsp.InitializeRequestParams) error {
+	srv.encoding = negotiateEncoding(req.Capabilities)
+	srv.capabilities.PositionEncoding = srv.encoding
 	return srv.setTrace(req.Trace)
 }
 
@@ -175,8 +186,12 @@ func (srv *Server) handleMessage(w io.Writer, msg []byte) {
 	handler(w, content, requestId)
 }
 
-func (srv *Server) getTokenAtPos(pos lsp.Position) (index int, tok token.Token, ok bool) {
-	toks := srv.parser.Tokens()
+func (doc *document) getTokenAtPos(lspPos lsp.Position) (index int, tok token.Token, ok bool) {
+	pos, err := doc.conv.fromLSP(lspPos)
+	if err != nil {
+		return -1, token.Token{}, false
+	}
+	toks := doc.parser.Tokens()
 	idx := slices.IndexFunc(toks, func(t token.Token) bool {
 		if t.StartPos.Line != pos.Line {
 			return false
@@ -0,0 +1,147 @@
+// Package analysis checks formula files in bulk, collecting their syntax errors,
+// type errors and lint diagnostics, and reports them in formats for people, tools
+// and CI systems.
+package analysis
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/lint"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// Diagnostic is a problem found in a formula.
+type Diagnostic struct {
+	Code     string // a stable identifier, such as syntax-error or the ID of a lint rule.
+	Severity lint.Severity
+	Msg      string
+	Start    token.Pos
+	End      token.Pos
+}
+
+// File is the result of checking a single formula file.
+type File struct {
+	Path        string
+	Source      string
+	Diagnostics []Diagnostic // sorted by position.
+	Err         error        // set if the file could not be read.
+}
+
+// Options control what is checked.
+type Options struct {
+	Lint    lint.Config      // configures the lint rules.
+	Catalog *catalog.Catalog // the names that formulas are checked against, if any.
+	Schemas object.Schemas   // the fields of records, object.DefaultSchemas if nil.
+	Workers int              // files checked at once, defaults to the number of CPUs.
+}
+
+// positional is an error that knows where in the formula it occurred, and which
+// stable code identifies it.
+type positional interface {
+	error
+	token.Positional
+	Code() string
+}
+
+// Check checks the formula src of the file at path. Diagnostics suppressed by
+// comments, as described by lint.Suppressor, are left out.
+func Check(path, src string, opts Options) File {
+	prs := parser.New(lexer.New(src), parser.WithCatalog(opts.Catalog), parser.WithSchemas(opts.Schemas))
+	return CheckParsed(path, src, prs, opts)
+}
+
+// CheckParsed is Check for a formula already parsed by prs, which is reused as is:
+// the Catalog and Schemas of opts are those prs was created with.
+func CheckParsed(path, src string, prs *parser.Parser, opts Options) File {
+	f := File{Path: path, Source: src, Diagnostics: []Diagnostic{}}
+	sup := lint.NewSuppressor(prs.Tokens())
+
+	errs := slices.Concat(prs.Errors(), prs.TypeErrors(), prs.Resolved().Errors)
+	for _, err := range errs {
+		d := Diagnostic{Code: "error", Severity: lint.SeverityError, Msg: parser.Message(err)}
+		var pErr positional
+		if errors.As(err, &pErr) {
+			d.Code = pErr.Code()
+			d.Start, d.End = pErr.Pos()
+		}
+		if !sup.Suppressed(d.Code, d.Start) {
+			f.Diagnostics = append(f.Diagnostics, d)
+		}
+	}
+	// formulas with syntax errors are not linted, so their suppressions of lint
+	// rules cannot be known to be unused.
+	linted := func(code string) bool { return len(prs.Errors()) == 0 || !lint.IsRule(code) }
+	lints := sup.Filter(lint.Lint(prs, opts.Lint))
+	for _, d := range append(lints, sup.Diagnostics(linted)...) {
+		f.Diagnostics = append(f.Diagnostics, Diagnostic{
+			Code:     d.Rule,
+			Severity: d.Severity,
+			Msg:      d.Msg,
+			Start:    d.Start,
+			End:      d.End,
+		})
+	}
+
+	slices.SortStableFunc(f.Diagnostics, func(a, b Diagnostic) int {
+		switch {
+		case a.Start.LT(b.Start):
+			return -1
+		case b.Start.LT(a.Start):
+			return 1
+		}
+		return 0
+	})
+	return f
+}
+
+// CheckFiles reads and checks the files at paths in parallel. The results are in
+// the order of paths.
+func CheckFiles(paths []string, opts Options) []File {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	files := make([]File, len(paths))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				src, err := os.ReadFile(paths[i])
+				if err != nil {
+					files[i] = File{Path: paths[i], Err: err}
+					continue
+				}
+				files[i] = Check(paths[i], string(src), opts)
+			}
+		}()
+	}
+	for i := range paths {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return files
+}
+
+// Fails returns true if d is at least as severe as threshold.
+func (d Diagnostic) Fails(threshold lint.Severity) bool {
+	return d.Severity != lint.SeverityOff && d.Severity <= threshold
+}
+
+// Failed returns true if the file could not be read, or has a diagnostic at
+// least as severe as threshold.
+func (f File) Failed(threshold lint.Severity) bool {
+	return f.Err != nil || slices.ContainsFunc(f.Diagnostics, func(d Diagnostic) bool { return d.Fails(threshold) })
+}
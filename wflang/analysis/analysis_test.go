@@ -0,0 +1,195 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/lint"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // "code line:col severity"
+	}{
+		{"clean", "var a = 1;\na + 1", nil},
+		{"lint", "var a = 1;\n2", []string{"unused-var 0:4 warning"}},
+		{"type error", `"a" + 1`, []string{"type-mismatch 0:0 error"}},
+		{"syntax error", "var a = 1;\nmax(", []string{"syntax-error 1:3 error", "arg-count 1:3 error"}},
+		{"sorted", "var a = 1;\n\"a\" + 1", []string{"unused-var 0:4 warning", "type-mismatch 1:0 error"}},
+		{"suppressed", "var a = 1; // wflang:ignore unused-var\n\"a\" + 1 // wflang:ignore type-mismatch", nil},
+		{"unused suppression", "// wflang:ignore type-mismatch\n1", []string{"unused-suppression 0:0 warning"}},
+		{"suppressed syntax error", "max( // wflang:ignore syntax-error", []string{"arg-count 0:33 error"}},
+		{"undefined", "var total = 1;\ntotl + 1", []string{"unused-var 0:4 warning", "undefined 1:0 error"}},
+		{"used before declaration", "var a = b;\nvar b = 1;\na", []string{"use-before-declaration 0:8 error"}},
+		{"bad var value", "var a = 1 +; a", []string{"syntax-error 0:11 error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Check("test.wflang", tt.input, Options{})
+			var have []string
+			for _, d := range f.Diagnostics {
+				have = append(have, fmt.Sprintf("%s %d:%d %s", d.Code, d.Start.Line, d.Start.Col, d.Severity))
+			}
+			if fmt.Sprint(have) != fmt.Sprint(tt.want) {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+
+	f := Check("test.wflang", "var a = 1;\n2", Options{Lint: lint.Config{"unused-var": {Severity: lint.SeverityOff}}})
+	if len(f.Diagnostics) != 0 {
+		t.Errorf("have %+v, want no diagnostics with the rule off", f.Diagnostics)
+	}
+
+	cat := &catalog.Catalog{}
+	cat.AddPolicySet(catalog.PolicySet{Name: "OT_CODES"})
+	f = Check("test.wflang", "count(over day alias d, where d.pay_code in set OT_CODE)", Options{Catalog: cat})
+	if len(f.Diagnostics) != 1 || f.Diagnostics[0].Code != "unknown-policy-set" || f.Diagnostics[0].Severity != lint.SeverityError {
+		t.Errorf("have %+v, want an unknown policy set error", f.Diagnostics)
+	}
+}
+
+func TestCheckParsed(t *testing.T) {
+	src := "var a = 1;\ntotl + \"a\""
+	want := Check("test.wflang", src, Options{})
+	have := CheckParsed("test.wflang", src, parser.New(lexer.New(src)), Options{})
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %+v, want %+v", have, want)
+	}
+}
+
+func TestCheckMessage(t *testing.T) {
+	f := Check("test.wflang", "var a = 1 +; a", Options{})
+	if len(f.Diagnostics) != 1 {
+		t.Fatalf("have %+v, want one diagnostic", f.Diagnostics)
+	}
+	if have, want := f.Diagnostics[0].Msg, "no prefix parser mapped for token type ;"; have != want {
+		t.Errorf("have %q, want %q", have, want)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := range 20 {
+		path := filepath.Join(dir, fmt.Sprintf("%02d.wflang", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("var v%d = 1;\n2", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	paths = append(paths, filepath.Join(dir, "missing.wflang"))
+
+	files := CheckFiles(paths, Options{Workers: 4})
+	for i, f := range files[:20] {
+		if f.Path != paths[i] || len(f.Diagnostics) != 1 || f.Diagnostics[0].Msg != fmt.Sprintf("var v%d is declared but never used", i) {
+			t.Errorf("file %d: have %+v", i, f)
+		}
+	}
+	if last := files[20]; last.Err == nil || !last.Failed(lint.SeverityError) {
+		t.Errorf("missing file: have %+v, want a failure", last)
+	}
+}
+
+func TestFailed(t *testing.T) {
+	f := Check("test.wflang", "var a = 1;\n2", Options{})
+	if f.Failed(lint.SeverityError) {
+		t.Error("a warning failed at the error threshold")
+	}
+	if !f.Failed(lint.SeverityWarning) || !f.Failed(lint.SeverityInfo) {
+		t.Error("a warning did not fail at the warning and info thresholds")
+	}
+}
+
+func testFiles() []File {
+	return []File{
+		Check("a.wflang", "var a = 1;\n\t(2)", Options{}),
+		{Path: "b.wflang", Err: os.ErrNotExist},
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	want := "a.wflang:1:5: warning: var a is declared but never used (unused-var)\n" +
+		"    var a = 1;\n" +
+		"        ^\n" +
+		"a.wflang:2:2: info: redundant parentheses (redundant-parens)\n" +
+		"    \t(2)\n" +
+		"    \t^^^\n" +
+		"b.wflang: file does not exist\n"
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, testFiles(), lint.SeverityError); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("have:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testFiles(), lint.SeverityError); err != nil {
+		t.Fatal(err)
+	}
+	var have []jsonFile
+	if err := json.Unmarshal(buf.Bytes(), &have); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonDiagnostic{Code: "redundant-parens", Severity: lint.SeverityInfo, Message: "redundant parentheses", Line: 2, Column: 2, EndLine: 2, EndColumn: 5}
+	if len(have) != 2 || len(have[0].Diagnostics) != 2 || have[0].Diagnostics[1] != want || have[1].Error == "" {
+		t.Errorf("have %+v", have)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testFiles(), lint.SeverityError); err != nil {
+		t.Fatal(err)
+	}
+	var have sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &have); err != nil {
+		t.Fatal(err)
+	}
+	if have.Version != "2.1.0" || len(have.Runs) != 1 {
+		t.Fatalf("have %+v", have)
+	}
+	run := have.Runs[0]
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("have %d results and %d rules, want 3 of each", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+	res := run.Results[1]
+	region := sarifRegion{StartLine: 2, StartColumn: 2, EndLine: 2, EndColumn: 5}
+	if res.RuleID != "redundant-parens" || res.Level != "note" || *res.Locations[0].PhysicalLocation.Region != region {
+		t.Errorf("have %+v", res)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, testFiles(), lint.SeverityWarning); err != nil {
+		t.Fatal(err)
+	}
+	var have junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &have); err != nil {
+		t.Fatal(err)
+	}
+	if have.Tests != 2 || have.Failures != 2 || len(have.Suites) != 1 {
+		t.Fatalf("have %+v", have)
+	}
+	failure := have.Suites[0].Cases[0].Failure
+	if failure == nil || failure.Message != "1 problem" {
+		t.Errorf("have failure %+v, want 1 problem", failure)
+	}
+}
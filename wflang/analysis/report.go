@@ -0,0 +1,309 @@
+package analysis
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/lint"
+)
+
+// Formats returns the report writers by the name of their format.
+func Formats() map[string]func(w io.Writer, files []File, threshold lint.Severity) error {
+	return map[string]func(io.Writer, []File, lint.Severity) error{
+		"text":  WriteText,
+		"json":  WriteJSON,
+		"sarif": WriteSARIF,
+		"junit": WriteJUnit,
+	}
+}
+
+// WriteText writes each diagnostic as a file:line:col line followed by the source
+// line, with carets under the range of the diagnostic. Lines and columns are
+// counted from 1. The threshold is not used.
+func WriteText(w io.Writer, files []File, _ lint.Severity) error {
+	for _, f := range files {
+		if f.Err != nil {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", f.Path, f.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		lines := strings.Split(f.Source, "\n")
+		for _, d := range f.Diagnostics {
+			fmt.Fprintf(w, "%s:%d:%d: %s: %s (%s)\n", f.Path, d.Start.Line+1, d.Start.Col+1, d.Severity, d.Msg, d.Code)
+			if _, err := io.WriteString(w, snippet(lines, d)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snippet returns the source line of d, indented, and carets under its range on
+// that line.
+func snippet(lines []string, d Diagnostic) string {
+	if int(d.Start.Line) >= len(lines) {
+		return ""
+	}
+	line := strings.TrimRight(lines[d.Start.Line], "\r")
+	col := min(int(d.Start.Col), len(line))
+	width := len(line) - col
+	if d.End.Line == d.Start.Line {
+		width = int(d.End.Col) - int(d.Start.Col) + 1
+	}
+
+	var pad strings.Builder
+	for _, r := range line[:col] {
+		if r == '\t' {
+			pad.WriteRune('\t') // keep tabs, so the carets line up whatever their width.
+		} else {
+			pad.WriteRune(' ')
+		}
+	}
+	return fmt.Sprintf("    %s\n    %s%s\n", line, pad.String(), strings.Repeat("^", max(width, 1)))
+}
+
+type jsonFile struct {
+	Path        string           `json:"path"`
+	Error       string           `json:"error,omitempty"`
+	Diagnostics []jsonDiagnostic `json:"diagnostics"`
+}
+
+type jsonDiagnostic struct {
+	Code      string        `json:"code"`
+	Severity  lint.Severity `json:"severity"`
+	Message   string        `json:"message"`
+	Line      uint          `json:"line"`
+	Column    uint          `json:"column"`
+	EndLine   uint          `json:"endLine"`
+	EndColumn uint          `json:"endColumn"` // exclusive.
+}
+
+// WriteJSON writes the files and their diagnostics as a JSON array. Lines and
+// columns are counted from 1, and the end column is exclusive. The threshold is
+// not used.
+func WriteJSON(w io.Writer, files []File, _ lint.Severity) error {
+	out := make([]jsonFile, len(files))
+	for i, f := range files {
+		out[i] = jsonFile{Path: f.Path, Diagnostics: []jsonDiagnostic{}}
+		if f.Err != nil {
+			out[i].Error = f.Err.Error()
+		}
+		for _, d := range f.Diagnostics {
+			out[i].Diagnostics = append(out[i].Diagnostics, jsonDiagnostic{
+				Code:      d.Code,
+				Severity:  d.Severity,
+				Message:   d.Msg,
+				Line:      d.Start.Line + 1,
+				Column:    d.Start.Col + 1,
+				EndLine:   d.End.Line + 1,
+				EndColumn: d.End.Col + 2,
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+// The subset of SARIF 2.1.0 that is written by WriteSARIF.
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name  string      `json:"name"`
+		Rules []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription *sarifText   `json:"shortDescription,omitempty"`
+		DefaultConfig    *sarifConfig `json:"defaultConfiguration,omitempty"`
+	}
+	sarifConfig struct {
+		Level string `json:"level"`
+	}
+	sarifText struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifText       `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysical `json:"physicalLocation"`
+	}
+	sarifPhysical struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   uint `json:"startLine"`
+		StartColumn uint `json:"startColumn"`
+		EndLine     uint `json:"endLine"`
+		EndColumn   uint `json:"endColumn"`
+	}
+)
+
+// sarifLevel returns the SARIF level of sev.
+func sarifLevel(sev lint.Severity) string {
+	switch sev {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// WriteSARIF writes the diagnostics as a SARIF 2.1.0 log with a single run, for
+// code scanning tools. Files that could not be read are reported under the rule
+// read-error. The threshold is not used.
+func WriteSARIF(w io.Writer, files []File, _ lint.Severity) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "wflang", Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	var ids []string
+	for _, f := range files {
+		loc := sarifLocation{PhysicalLocation: sarifPhysical{ArtifactLocation: sarifArtifact{URI: sarifURI(f.Path)}}}
+		if f.Err != nil {
+			ids = append(ids, "read-error")
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    "read-error",
+				Level:     "error",
+				Message:   sarifText{f.Err.Error()},
+				Locations: []sarifLocation{loc},
+			})
+		}
+		for _, d := range f.Diagnostics {
+			ids = append(ids, d.Code)
+			dLoc := loc
+			dLoc.PhysicalLocation.Region = &sarifRegion{
+				StartLine:   d.Start.Line + 1,
+				StartColumn: d.Start.Col + 1,
+				EndLine:     d.End.Line + 1,
+				EndColumn:   d.End.Col + 2,
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    d.Code,
+				Level:     sarifLevel(d.Severity),
+				Message:   sarifText{d.Msg},
+				Locations: []sarifLocation{dLoc},
+			})
+		}
+	}
+
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		rule := sarifRule{ID: id}
+		for _, r := range lint.Rules() {
+			if r.ID() == id {
+				rule.ShortDescription = &sarifText{r.Doc()}
+				rule.DefaultConfig = &sarifConfig{sarifLevel(r.Severity())}
+			}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifURI returns path as a relative URI reference, which SARIF requires to use
+// forward slashes.
+func sarifURI(path string) string {
+	return strings.ReplaceAll(path, `\`, "/")
+}
+
+type (
+	junitSuites struct {
+		XMLName  xml.Name     `xml:"testsuites"`
+		Tests    int          `xml:"tests,attr"`
+		Failures int          `xml:"failures,attr"`
+		Suites   []junitSuite `xml:"testsuite"`
+	}
+	junitSuite struct {
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Cases    []junitCase `xml:"testcase"`
+	}
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Body    string `xml:",chardata"`
+	}
+)
+
+// WriteJUnit writes a JUnit XML report with a test case per file, which fails if
+// the file has diagnostics at least as severe as threshold, or cannot be read.
+func WriteJUnit(w io.Writer, files []File, threshold lint.Severity) error {
+	suite := junitSuite{Name: "wflang check", Tests: len(files)}
+	for _, f := range files {
+		c := junitCase{Name: f.Path, ClassName: "wflang"}
+		switch {
+		case f.Err != nil:
+			c.Failure = &junitFailure{Message: f.Err.Error(), Type: "read-error"}
+		case f.Failed(threshold):
+			var body strings.Builder
+			n := 0
+			for _, d := range f.Diagnostics {
+				if d.Fails(threshold) {
+					n++
+					fmt.Fprintf(&body, "%s:%d:%d: %s: %s (%s)\n", f.Path, d.Start.Line+1, d.Start.Col+1, d.Severity, d.Msg, d.Code)
+				}
+			}
+			msg := fmt.Sprintf("%d problems", n)
+			if n == 1 {
+				msg = "1 problem"
+			}
+			c.Failure = &junitFailure{Message: msg, Type: threshold.String(), Body: body.String()}
+		}
+		if c.Failure != nil {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -1,6 +1,7 @@
 // AST (Abstract Syntax Tree) package defines the structure of the AST that the
 // parser will generate from the token stream produced by the lexer. The AST is
-// the last step for WFLang, which does not require an interpreter or compiler.
+// the last step for the language server, while formulas can be executed locally
+// by walking it with the eval package.
 package ast
 
 import (
@@ -220,6 +221,56 @@ func (i InfixExpression) Pos() (start, end token.Pos) {
 	return start, end
 }
 
+// MemberExpression is an expression that accesses a member of an object, such as
+// a field of a record alias (x.hours) or a property of a summary context
+// (period.end), or calls a method of a record (x.hasTimes()). The embedded token
+// is the period.
+type MemberExpression struct {
+	token.Token
+	Object Expression
+	Member Ident
+	Call   bool         // the member is a method called with Args.
+	LPar   token.Token  // zero if not Call.
+	Args   []Expression // nil if not Call.
+	RPar   token.Token  // zero if not Call.
+}
+
+func (m MemberExpression) ExpressionNode()      {}
+func (m MemberExpression) TokenLiteral() string { return m.Token.Literal }
+
+func (m MemberExpression) String() string {
+	var out strings.Builder
+	if m.Object != nil {
+		out.WriteString(m.Object.String())
+	}
+	out.WriteString("." + m.Member.String())
+	if m.Call {
+		out.WriteString("(")
+		for i, arg := range m.Args {
+			out.WriteString(arg.String())
+			if i < len(m.Args)-1 {
+				out.WriteString(", ")
+			}
+		}
+		out.WriteString(")")
+	}
+	return out.String()
+}
+
+// Pos returns the StartPos of the Object expression, and the EndPos of the Member,
+// or of the closing parenthesis of a method call.
+func (m MemberExpression) Pos() (start, end token.Pos) {
+	start = m.Token.StartPos
+	if m.Object != nil {
+		start, _ = m.Object.Pos()
+	}
+	_, end = m.Member.Pos()
+	if m.Call {
+		end = m.RPar.EndPos
+	}
+	return start, end
+}
+
 // StringLiteral is an expression that represents a string literal. The string
 // itself is stored in the token literal.
 type StringLiteral struct {
@@ -246,6 +297,18 @@ func (b BooleanLiteral) Pos() (start, end token.Pos) {
 	return b.Token.StartPos, b.Token.EndPos
 }
 
+// NullLiteral is an expression that represents the null keyword.
+type NullLiteral struct {
+	token.Token
+}
+
+func (n NullLiteral) ExpressionNode()      {}
+func (n NullLiteral) TokenLiteral() string { return n.Token.Literal }
+func (n NullLiteral) String() string       { return "null" }
+func (n NullLiteral) Pos() (start, end token.Pos) {
+	return n.Token.StartPos, n.Token.EndPos
+}
+
 // BlockExpression is a group of 0-n number of VarStatements followed
 // by a single Expression. The VarStatements are optional but the Expression is
 // mandatory, there can only be one, and it must be the last member of the group.
@@ -273,6 +336,15 @@ func (b BlockExpression) Pos() (start, end token.Pos) {
 	return b.StartPos, end
 }
 
+// Unblock returns the value of n if it is a block without vars, as are the
+// arguments of calls.
+func Unblock(n Expression) Expression {
+	if block, ok := n.(BlockExpression); ok && len(block.Vars) == 0 {
+		return block.Value
+	}
+	return n
+}
+
 type ParenExpression struct {
 	token.Token
 	Inner  Expression
@@ -399,10 +471,16 @@ type InExpression struct {
 
 func (i InExpression) ExpressionNode()      {}
 func (i InExpression) TokenLiteral() string { return i.Token.Literal }
-func (i InExpression) String() string       { return "in " + i.List.String() }
+func (i InExpression) String() string {
+	return fmt.Sprintf("(%s in %s)", i.Left.String(), i.List.String())
+}
 
+// Pos returns the StartPos of the Left expression, and the EndPos of the List.
 func (i InExpression) Pos() (start, end token.Pos) {
 	start = i.Token.StartPos
+	if i.Left != nil {
+		start, _ = i.Left.Pos()
+	}
 	_, end = i.List.Pos()
 	return start, end
 }
@@ -423,10 +501,10 @@ func (l ListLiteral) String() string {
 	out.WriteByte('[')
 
 	for i, str := range l.Strings {
-		out.WriteString(str.String())
-		if i >= len(l.Strings)-1 {
+		if i > 0 {
 			out.WriteString(", ")
 		}
+		out.WriteString(str.String())
 	}
 
 	out.WriteByte(']')
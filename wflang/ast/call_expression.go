@@ -34,10 +34,10 @@ func (f BuiltinCall) String() string {
 	var out strings.Builder
 	out.WriteString(f.Name + "(")
 	for i, arg := range f.Args {
-		out.WriteString(arg.String())
-		if i == len(f.Args)-1 {
-			out.WriteString(",")
+		if i > 0 {
+			out.WriteString(", ")
 		}
+		out.WriteString(arg.String())
 	}
 	out.WriteString(")")
 	return out.String()
@@ -0,0 +1,26 @@
+package ast
+
+import "github.com/scatternoodle/wflang/wflang/token"
+
+// ParseErr is a struct that represents an error that occurred during the parsing
+// process. It implements both expression and statement interfaces in addition to the
+// error interface, so that it can also traverse the AST in place of a valid node.
+//
+// The parser inserts a ParseErr wherever it had to give up on a node and recover,
+// so the rest of the tree remains usable while the document contains errors.
+type ParseErr struct {
+	Msg   string
+	Token token.Token // the token at which the error was detected.
+}
+
+func (p ParseErr) Error() string        { return p.Msg }
+func (p ParseErr) String() string       { return p.Error() }
+func (p ParseErr) TokenLiteral() string { return p.Token.Literal }
+func (p ParseErr) ExpressionNode()      {}
+func (p ParseErr) StatementNode()       {}
+
+// Code returns the stable diagnostic code shared by all syntax errors.
+func (p ParseErr) Code() string { return "syntax-error" }
+
+// Pos returns the StartPos and EndPos of the token at which the error was detected.
+func (p ParseErr) Pos() (start, end token.Pos) { return p.Token.StartPos, p.Token.EndPos }
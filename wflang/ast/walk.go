@@ -59,6 +59,14 @@ func Walk(v Visitor, node Node) {
 		if n.Right != nil {
 			Walk(v, n.Right)
 		}
+	case MemberExpression:
+		if n.Object != nil {
+			Walk(v, n.Object)
+		}
+		Walk(v, n.Member)
+		if len(n.Args) > 0 {
+			walkList(v, n.Args)
+		}
 	case BlockExpression:
 		if len(n.Vars) != 0 {
 			walkList(v, n.Vars)
@@ -85,6 +93,8 @@ func Walk(v Visitor, node Node) {
 		if n.HasAlias {
 			Walk(v, n.Alias)
 		}
+	case AliasExpression:
+		Walk(v, n.Alias)
 	case WhereExpression:
 		if n.Condition != nil {
 			Walk(v, n.Condition)
@@ -101,8 +111,8 @@ func Walk(v Visitor, node Node) {
 			Walk(v, n.List)
 		}
 	case Ident, LineCommentStatement, BlockCommentStatement, NumberLiteral,
-		StringLiteral, BooleanLiteral, BlankExpression, ListLiteral,
-		SetExpression, DateLiteral, TimeLiteral:
+		StringLiteral, BooleanLiteral, NullLiteral, BlankExpression, ListLiteral,
+		SetExpression, DateLiteral, TimeLiteral, ParseErr:
 		// nothing to do as these do not have child nodes
 	default:
 		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
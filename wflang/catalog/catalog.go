@@ -0,0 +1,278 @@
+// Package catalog holds the names that formulas refer to but that are defined
+// elsewhere in the platform, such as policy sets, pay codes, employee attributes
+// and macros, loaded from local CSV or JSON exports so that formulas can be
+// checked against them.
+package catalog
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// PolicySet is a named set of pay codes.
+type PolicySet struct {
+	Name        string   `json:"name"`
+	Members     []string `json:"members"`
+	Description string   `json:"description,omitempty"`
+}
+
+// PayCode is a code that classifies time.
+type PayCode struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+// Attribute is an employee attribute, whose value may change over time.
+type Attribute struct {
+	Name           string     `json:"name"`
+	Type           types.Type `json:"type"` // one of number, string, date or boolean.
+	Description    string     `json:"description,omitempty"`
+	EffectiveDated bool       `json:"effectiveDated,omitempty"` // the value depends on the date it is read on.
+}
+
+// Macro is a named fragment of formula defined in a macro library, which formulas
+// use as $NAME(args)$.
+type Macro struct {
+	Name        string     `json:"name"`
+	Type        types.Type `json:"type,omitempty"` // of the value it expands to, or any if not given.
+	Description string     `json:"description,omitempty"`
+}
+
+// valueTypes returns the types that attributes and macros may have, by their names
+// in catalogue files.
+func valueTypes() map[string]types.Type {
+	return map[string]types.Type{
+		"number":  types.T_NUMBER,
+		"string":  types.T_STRING,
+		"date":    types.T_DATE,
+		"boolean": types.T_BOOL,
+	}
+}
+
+// Catalog is a collection of known names. The zero Catalog, and a nil *Catalog,
+// are empty and check nothing.
+type Catalog struct {
+	policySets map[string]PolicySet // keyed by lowercase name, as set names are case insensitive.
+	payCodes   map[string]PayCode
+	attributes map[string]Attribute // keyed by lowercase name, as attribute IDs are idents.
+	macros     map[string]Macro     // keyed by lowercase name, as macro names are idents.
+}
+
+// AddPolicySet adds set, replacing any set of the same name.
+func (c *Catalog) AddPolicySet(set PolicySet) {
+	if c.policySets == nil {
+		c.policySets = map[string]PolicySet{}
+	}
+	c.policySets[strings.ToLower(set.Name)] = set
+}
+
+// AddPayCode adds code, replacing any pay code that is the same.
+func (c *Catalog) AddPayCode(code PayCode) {
+	if c.payCodes == nil {
+		c.payCodes = map[string]PayCode{}
+	}
+	c.payCodes[code.Code] = code
+}
+
+// AddAttribute adds attr, replacing any attribute of the same name.
+func (c *Catalog) AddAttribute(attr Attribute) {
+	if c.attributes == nil {
+		c.attributes = map[string]Attribute{}
+	}
+	c.attributes[strings.ToLower(attr.Name)] = attr
+}
+
+// AddMacro adds macro, replacing any macro of the same name.
+func (c *Catalog) AddMacro(macro Macro) {
+	if c.macros == nil {
+		c.macros = map[string]Macro{}
+	}
+	c.macros[strings.ToLower(macro.Name)] = macro
+}
+
+// HasPolicySets returns true if any policy sets are known, and so can be checked.
+func (c *Catalog) HasPolicySets() bool { return c != nil && len(c.policySets) > 0 }
+
+// HasPayCodes returns true if any pay codes are known, and so can be checked.
+func (c *Catalog) HasPayCodes() bool { return c != nil && len(c.payCodes) > 0 }
+
+// HasAttributes returns true if any employee attributes are known, and so can be
+// checked.
+func (c *Catalog) HasAttributes() bool { return c != nil && len(c.attributes) > 0 }
+
+// HasMacros returns true if any macros are known, and so can be checked.
+func (c *Catalog) HasMacros() bool { return c != nil && len(c.macros) > 0 }
+
+// PolicySet returns the policy set called name, ignoring case.
+func (c *Catalog) PolicySet(name string) (PolicySet, bool) {
+	if c == nil {
+		return PolicySet{}, false
+	}
+	set, ok := c.policySets[strings.ToLower(name)]
+	return set, ok
+}
+
+// PayCode returns the pay code code, which is case sensitive.
+func (c *Catalog) PayCode(code string) (PayCode, bool) {
+	if c == nil {
+		return PayCode{}, false
+	}
+	pc, ok := c.payCodes[code]
+	return pc, ok
+}
+
+// Attribute returns the employee attribute called name, ignoring case.
+func (c *Catalog) Attribute(name string) (Attribute, bool) {
+	if c == nil {
+		return Attribute{}, false
+	}
+	attr, ok := c.attributes[strings.ToLower(name)]
+	return attr, ok
+}
+
+// Macro returns the macro called name, ignoring case.
+func (c *Catalog) Macro(name string) (Macro, bool) {
+	if c == nil {
+		return Macro{}, false
+	}
+	macro, ok := c.macros[strings.ToLower(name)]
+	return macro, ok
+}
+
+// PolicySets returns the known policy sets, sorted by name.
+func (c *Catalog) PolicySets() []PolicySet {
+	if c == nil {
+		return nil
+	}
+	sets := make([]PolicySet, 0, len(c.policySets))
+	for _, set := range c.policySets {
+		sets = append(sets, set)
+	}
+	slices.SortFunc(sets, func(a, b PolicySet) int { return strings.Compare(a.Name, b.Name) })
+	return sets
+}
+
+// PayCodes returns the known pay codes, sorted.
+func (c *Catalog) PayCodes() []PayCode {
+	if c == nil {
+		return nil
+	}
+	codes := make([]PayCode, 0, len(c.payCodes))
+	for _, pc := range c.payCodes {
+		codes = append(codes, pc)
+	}
+	slices.SortFunc(codes, func(a, b PayCode) int { return strings.Compare(a.Code, b.Code) })
+	return codes
+}
+
+// Attributes returns the known employee attributes, sorted by name.
+func (c *Catalog) Attributes() []Attribute {
+	if c == nil {
+		return nil
+	}
+	attrs := make([]Attribute, 0, len(c.attributes))
+	for _, attr := range c.attributes {
+		attrs = append(attrs, attr)
+	}
+	slices.SortFunc(attrs, func(a, b Attribute) int { return strings.Compare(a.Name, b.Name) })
+	return attrs
+}
+
+// Macros returns the known macros, sorted by name.
+func (c *Catalog) Macros() []Macro {
+	if c == nil {
+		return nil
+	}
+	macros := make([]Macro, 0, len(c.macros))
+	for _, macro := range c.macros {
+		macros = append(macros, macro)
+	}
+	slices.SortFunc(macros, func(a, b Macro) int { return strings.Compare(a.Name, b.Name) })
+	return macros
+}
+
+// SuggestPolicySet returns the name of the known policy set closest to name, if
+// any is close enough to be a likely typo.
+func (c *Catalog) SuggestPolicySet(name string) (string, bool) {
+	var names []string
+	for _, set := range c.PolicySets() {
+		names = append(names, set.Name)
+	}
+	return Suggest(name, names)
+}
+
+// SuggestPayCode returns the known pay code closest to code, if any is close
+// enough to be a likely typo.
+func (c *Catalog) SuggestPayCode(code string) (string, bool) {
+	var codes []string
+	for _, pc := range c.PayCodes() {
+		codes = append(codes, pc.Code)
+	}
+	return Suggest(code, codes)
+}
+
+// SuggestAttribute returns the name of the known employee attribute closest to
+// name, if any is close enough to be a likely typo.
+func (c *Catalog) SuggestAttribute(name string) (string, bool) {
+	var names []string
+	for _, attr := range c.Attributes() {
+		names = append(names, attr.Name)
+	}
+	return Suggest(name, names)
+}
+
+// SuggestMacro returns the name of the known macro closest to name, if any is
+// close enough to be a likely typo.
+func (c *Catalog) SuggestMacro(name string) (string, bool) {
+	var names []string
+	for _, macro := range c.Macros() {
+		names = append(names, macro.Name)
+	}
+	return Suggest(name, names)
+}
+
+// Suggest returns the candidate closest to name by edit distance, ignoring case,
+// if it is within a third of the length of name. Ties go to the earliest
+// candidate.
+func Suggest(name string, candidates []string) (string, bool) {
+	name = strings.ToLower(name)
+	limit := max(1, len([]rune(name))/3)
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		if d := distance(name, strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best, best != ""
+}
+
+// distance returns the edit distance between a and b in runes, counting the
+// transposition of adjacent runes as a single edit, as it is a common typo.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := [3][]int{} // the rows for i-2, i-1 and i.
+	for r := range rows {
+		rows[r] = make([]int, len(rb)+1)
+	}
+	for j := range rows[1] {
+		rows[1][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		prev2, prev, curr := rows[0], rows[1], rows[2]
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		rows[0], rows[1], rows[2] = prev, curr, prev2
+	}
+	return rows[1][len(rb)]
+}
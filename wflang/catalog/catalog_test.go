@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"reg", "reg", 0},
+		{"reg", "", 3},
+		{"reg", "rge", 1},
+		{"kitten", "sitting", 3},
+		{"über", "uber", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if have := distance(tt.a, tt.b); have != tt.want {
+				t.Errorf("have %d, want %d", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"OVERTIME", "OT", "REG", "REGULAR_HOURS"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"ot", "OT"},
+		{"OVERTMIE", "OVERTIME"},
+		{"REGULAR_HORUS", "REGULAR_HOURS"},
+		{"SICK", ""},
+		{"X", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, ok := Suggest(tt.name, candidates)
+			if have != tt.want || ok != (tt.want != "") {
+				t.Errorf("have %q, %t, want %q", have, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cat := &Catalog{}
+	if err := cat.LoadPolicySets(write("sets.csv", "Set,Pay_Code,Description\nOT_CODES,OT,overtime\nOT_CODES,DT,\nWORKED,REG,\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cat.LoadPolicySets(write("sets.json", `[{"name": "LEAVE", "members": ["SICK", "VAC"]}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := cat.LoadPayCodes(write("codes.csv", "code,description\nREG,Regular\nOT,Overtime\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cat.LoadPayCodes(write("codes.json", `[{"code": "SICK"}]`)); err != nil {
+		t.Fatal(err)
+	}
+
+	set, ok := cat.PolicySet("ot_codes")
+	if !ok || fmt.Sprint(set.Members) != "[OT DT]" || set.Description != "overtime" {
+		t.Errorf("have %+v, %t", set, ok)
+	}
+	if have := fmt.Sprint(cat.PolicySets()); have != "[{LEAVE [SICK VAC] } {OT_CODES [OT DT] overtime} {WORKED [REG] }]" {
+		t.Errorf("have policy sets %s", have)
+	}
+	if have := fmt.Sprint(cat.PayCodes()); have != "[{OT Overtime} {REG Regular} {SICK }]" {
+		t.Errorf("have pay codes %s", have)
+	}
+
+	if err := cat.LoadAttributes(write("attrs.csv", "name,type,description,effective_dated\nHOURLY_RATE,Number,Base rate,true\nUNION,string,,\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cat.LoadAttributes(write("attrs.json", `[{"name": "START_DATE", "type": "date"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if have := fmt.Sprint(cat.Attributes()); have != "[{HOURLY_RATE number Base rate true} {START_DATE date  false} {UNION string  false}]" {
+		t.Errorf("have attributes %s", have)
+	}
+	for name, data := range map[string]string{
+		"bad-type.csv":  "name,type\nRATE,currency\n",
+		"bad-dated.csv": "name,type,effective_dated\nRATE,number,sometimes\n",
+		"bad-type.json": `[{"name": "RATE", "type": "currency"}]`,
+	} {
+		if err := cat.LoadAttributes(write(name, data)); err == nil {
+			t.Errorf("%s: have no error", name)
+		}
+	}
+
+	if err := cat.LoadMacros(write("macros.csv", "name,type,description\nGO_LIVE_DATE,Date,First day live\nRULE,,\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cat.LoadMacros(write("macros.json", `[{"name": "BASE_HOURS", "type": "number"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if have := fmt.Sprint(cat.Macros()); have != "[{BASE_HOURS number } {GO_LIVE_DATE date First day live} {RULE any }]" {
+		t.Errorf("have macros %s", have)
+	}
+	if have, ok := cat.SuggestMacro("GO_LIVE_DAET"); !ok || have != "GO_LIVE_DATE" {
+		t.Errorf("have suggestion %q, %t", have, ok)
+	}
+	for name, data := range map[string]string{
+		"bad-type.csv":  "name,type\nRULE,text\n",
+		"no-name.json":  `[{"type": "number"}]`,
+		"bad-type.json": `[{"name": "RULE", "type": "text"}]`,
+	} {
+		if err := cat.LoadMacros(write(name, data)); err == nil {
+			t.Errorf("%s: have no error", name)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"missing.csv": "name,description\nREG,Regular\n",
+		"empty.csv":   "code\n\n ,\n",
+		"bad.json":    `{"code": "REG"}`,
+		"codes.txt":   "REG\n",
+	} {
+		if err := cat.LoadPayCodes(write(name, data)); err == nil {
+			t.Errorf("%s: have no error", name)
+		}
+	}
+}
+
+func TestNilCatalog(t *testing.T) {
+	var cat *Catalog
+	if cat.HasPolicySets() || cat.HasPayCodes() || cat.HasMacros() || len(cat.PayCodes()) > 0 {
+		t.Error("a nil catalog is not empty")
+	}
+	if _, ok := cat.PolicySet("x"); ok {
+		t.Error("a nil catalog has a policy set")
+	}
+}
@@ -0,0 +1,246 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// LoadPolicySets adds the policy sets exported to the file at path, which is
+// either JSON, an array of PolicySet objects, or CSV with a header row and a row
+// per member of each set, in the columns "set" and "pay_code", and optionally
+// "description".
+func (c *Catalog) LoadPolicySets(path string) error {
+	return load(path, c.readPolicySetsJSON, c.readPolicySetsCSV)
+}
+
+// LoadPayCodes adds the pay codes exported to the file at path, which is either
+// JSON, an array of PayCode objects, or CSV with a header row and the columns
+// "code", and optionally "description".
+func (c *Catalog) LoadPayCodes(path string) error {
+	return load(path, c.readPayCodesJSON, c.readPayCodesCSV)
+}
+
+// LoadAttributes adds the employee attributes exported to the file at path, which
+// is either JSON, an array of Attribute objects, or CSV with a header row and the
+// columns "name" and "type", and optionally "description" and "effective_dated",
+// which is true or false.
+func (c *Catalog) LoadAttributes(path string) error {
+	return load(path, c.readAttributesJSON, c.readAttributesCSV)
+}
+
+// LoadMacros adds the macros of the library exported to the file at path, which
+// is either JSON, an array of Macro objects, or CSV with a header row and the
+// column "name", and optionally "type" and "description". Macros without a type
+// expand to a value of any type.
+func (c *Catalog) LoadMacros(path string) error {
+	return load(path, c.readMacrosJSON, c.readMacrosCSV)
+}
+
+// load reads the file at path with readJSON or readCSV, depending on its extension.
+func load(path string, readJSON, readCSV func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = readJSON(f)
+	case ".csv":
+		err = readCSV(f)
+	default:
+		err = fmt.Errorf("unsupported catalogue format %q, want .json or .csv", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Catalog) readPolicySetsJSON(r io.Reader) error {
+	var sets []PolicySet
+	if err := json.NewDecoder(r).Decode(&sets); err != nil {
+		return err
+	}
+	for i, set := range sets {
+		if set.Name == "" {
+			return fmt.Errorf("policy set %d has no name", i)
+		}
+		c.AddPolicySet(set)
+	}
+	return nil
+}
+
+func (c *Catalog) readPolicySetsCSV(r io.Reader) error {
+	sets := map[string]*PolicySet{}
+	var order []string
+	err := readCSV(r, []string{"set", "pay_code"}, func(row map[string]string) error {
+		set, ok := sets[row["set"]]
+		if !ok {
+			set = &PolicySet{Name: row["set"]}
+			sets[set.Name] = set
+			order = append(order, set.Name)
+		}
+		if row["pay_code"] != "" {
+			set.Members = append(set.Members, row["pay_code"])
+		}
+		if row["description"] != "" {
+			set.Description = row["description"]
+		}
+		return nil
+	})
+	for _, name := range order {
+		c.AddPolicySet(*sets[name])
+	}
+	return err
+}
+
+func (c *Catalog) readPayCodesJSON(r io.Reader) error {
+	var codes []PayCode
+	if err := json.NewDecoder(r).Decode(&codes); err != nil {
+		return err
+	}
+	for i, pc := range codes {
+		if pc.Code == "" {
+			return fmt.Errorf("pay code %d has no code", i)
+		}
+		c.AddPayCode(pc)
+	}
+	return nil
+}
+
+func (c *Catalog) readPayCodesCSV(r io.Reader) error {
+	return readCSV(r, []string{"code"}, func(row map[string]string) error {
+		c.AddPayCode(PayCode{Code: row["code"], Description: row["description"]})
+		return nil
+	})
+}
+
+func (c *Catalog) readAttributesJSON(r io.Reader) error {
+	var attrs []Attribute
+	if err := json.NewDecoder(r).Decode(&attrs); err != nil {
+		return err
+	}
+	for i, attr := range attrs {
+		if attr.Name == "" {
+			return fmt.Errorf("attribute %d has no name", i)
+		}
+		typ, ok := valueTypes()[strings.ToLower(string(attr.Type))]
+		if !ok {
+			return fmt.Errorf("attribute %s: invalid type %q, want number, string, date or boolean", attr.Name, attr.Type)
+		}
+		attr.Type = typ
+		c.AddAttribute(attr)
+	}
+	return nil
+}
+
+func (c *Catalog) readAttributesCSV(r io.Reader) error {
+	return readCSV(r, []string{"name", "type"}, func(row map[string]string) error {
+		typ, ok := valueTypes()[strings.ToLower(row["type"])]
+		if !ok {
+			return fmt.Errorf("attribute %s: invalid type %q, want number, string, date or boolean", row["name"], row["type"])
+		}
+		attr := Attribute{Name: row["name"], Type: typ, Description: row["description"]}
+		if dated := row["effective_dated"]; dated != "" {
+			var err error
+			if attr.EffectiveDated, err = strconv.ParseBool(dated); err != nil {
+				return fmt.Errorf("attribute %s: invalid effective_dated %q, want true or false", attr.Name, dated)
+			}
+		}
+		c.AddAttribute(attr)
+		return nil
+	})
+}
+
+func (c *Catalog) readMacrosJSON(r io.Reader) error {
+	var macros []Macro
+	if err := json.NewDecoder(r).Decode(&macros); err != nil {
+		return err
+	}
+	for i, macro := range macros {
+		if macro.Name == "" {
+			return fmt.Errorf("macro %d has no name", i)
+		}
+		if err := c.addMacro(macro); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) readMacrosCSV(r io.Reader) error {
+	return readCSV(r, []string{"name"}, func(row map[string]string) error {
+		return c.addMacro(Macro{Name: row["name"], Type: types.Type(row["type"]), Description: row["description"]})
+	})
+}
+
+// addMacro adds macro once its type, if any, is validated.
+func (c *Catalog) addMacro(macro Macro) error {
+	if macro.Type == "" {
+		macro.Type = types.T_ANY
+	} else {
+		typ, ok := valueTypes()[strings.ToLower(string(macro.Type))]
+		if !ok {
+			return fmt.Errorf("macro %s: invalid type %q, want number, string, date or boolean", macro.Name, macro.Type)
+		}
+		macro.Type = typ
+	}
+	c.AddMacro(macro)
+	return nil
+}
+
+// readCSV reads CSV with a header row, calling fn with each later row keyed by the
+// lowercase names of the header. The columns in required must be present, and
+// their first column must not be empty.
+func readCSV(r io.Reader, required []string, fn func(row map[string]string) error) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return errors.New("missing header row")
+	}
+	if err != nil {
+		return err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, col := range required {
+		if !slices.Contains(header, col) {
+			return fmt.Errorf("missing column %q", col)
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row := map[string]string{}
+		for i, v := range record {
+			row[header[i]] = strings.TrimSpace(v)
+		}
+		if row[required[0]] == "" {
+			line, _ := cr.FieldPos(0)
+			return fmt.Errorf("line %d: empty %s", line, required[0])
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package config loads project configuration from .wflang.json files, which
+// configure the lint rules, the formatter and the catalogues that formulas are
+// checked against.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/format"
+	"github.com/scatternoodle/wflang/wflang/lint"
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// FileName is the name of project configuration files.
+const FileName = ".wflang.json"
+
+// Config is the configuration of a project.
+type Config struct {
+	Platform string         `json:"platform,omitempty"` // the version of the platform the formulas target, such as "9.2".
+	Lint     lint.Config    `json:"lint,omitempty"`
+	Format   Format         `json:"format,omitempty"`
+	Catalogs Catalogs       `json:"catalogs,omitempty"`
+	Records  object.Schemas `json:"records,omitempty"` // fields added to the default schemas of records.
+}
+
+// Format configures the formatter. Zero values leave the defaults.
+type Format struct {
+	Indent    int `json:"indent,omitempty"`
+	LineWidth int `json:"lineWidth,omitempty"`
+}
+
+// Options returns the format.Options configured by f.
+func (f Format) Options() format.Options {
+	opts := format.DefaultOptions()
+	if f.Indent > 0 {
+		opts.Indent = f.Indent
+	}
+	if f.LineWidth > 0 {
+		opts.LineWidth = f.LineWidth
+	}
+	return opts
+}
+
+// Catalogs holds the paths of catalogue files, relative to the directory of the
+// configuration file until it is loaded.
+type Catalogs struct {
+	PolicySets []string `json:"policySets,omitempty"`
+	PayCodes   []string `json:"payCodes,omitempty"`
+	Attributes []string `json:"employeeAttributes,omitempty"`
+	Macros     []string `json:"macros,omitempty"`
+}
+
+// resolve makes the relative paths of c relative to dir instead.
+func (c *Catalogs) resolve(dir string) {
+	for _, paths := range [][]string{c.PolicySets, c.PayCodes, c.Attributes, c.Macros} {
+		for i, path := range paths {
+			if !filepath.IsAbs(path) {
+				paths[i] = filepath.Join(dir, path)
+			}
+		}
+	}
+}
+
+// Load loads the policy sets, pay codes, employee attributes and macros of the
+// catalogue files into a single catalog.Catalog.
+func (c Catalogs) Load() (*catalog.Catalog, error) {
+	cat := &catalog.Catalog{}
+	for _, path := range c.PolicySets {
+		if err := cat.LoadPolicySets(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range c.PayCodes {
+		if err := cat.LoadPayCodes(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range c.Attributes {
+		if err := cat.LoadAttributes(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range c.Macros {
+		if err := cat.LoadMacros(path); err != nil {
+			return nil, err
+		}
+	}
+	return cat, nil
+}
+
+// Paths returns the paths of all the catalogue files.
+func (c Catalogs) Paths() []string {
+	return slices.Concat(c.PolicySets, c.PayCodes, c.Attributes, c.Macros)
+}
+
+var platformVersion = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// Validate returns an error if any part of the configuration is invalid.
+func (c Config) Validate() error {
+	if c.Platform != "" && !platformVersion.MatchString(c.Platform) {
+		return fmt.Errorf("invalid platform version %q, want numbers separated by dots", c.Platform)
+	}
+	if c.Format.Indent < 0 || c.Format.LineWidth < 0 {
+		return errors.New("format indent and lineWidth cannot be negative")
+	}
+	if err := c.Records.Validate(); err != nil {
+		return fmt.Errorf("records: %w", err)
+	}
+	return c.Lint.Validate()
+}
+
+// Schemas returns the default schemas of records, extended by the fields of
+// Records.
+func (c Config) Schemas() object.Schemas {
+	return object.DefaultSchemas().Extend(c.Records)
+}
+
+// Parse parses and validates the configuration data. Unknown fields are errors,
+// so that misspelt settings are not silently ignored.
+func Parse(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Load loads the configuration file at path, resolving its catalogue paths
+// against the directory of the file.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Catalogs.resolve(filepath.Dir(path))
+	return cfg, nil
+}
+
+// Find returns the path of the configuration file in dir, or the closest of its
+// parents, or false if there is none.
+func Find(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Discover loads the configuration file found from dir by Find. Without one, it
+// returns the zero Config, which leaves everything at its defaults.
+func Discover(dir string) (Config, error) {
+	path, ok := Find(dir)
+	if !ok {
+		return Config{}, nil
+	}
+	cfg, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil // removed since it was found.
+	}
+	return cfg, err
+}
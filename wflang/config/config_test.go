@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/lint"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", `{}`, false},
+		{"full", `{
+			"platform": "9.2",
+			"lint": {"unused-var": {"severity": "error"}},
+			"format": {"indent": 2, "lineWidth": 80},
+			"catalogs": {"policySets": ["sets.csv"], "payCodes": ["codes.json"], "employeeAttributes": ["attrs.json"], "macros": ["macros"]},
+			"records": {"timeRecord": [{"name": "DEPARTMENT", "type": "string", "description": "the department worked in."}]}
+		}`, false},
+		{"unknown field", `{"platfrom": "9.2"}`, true},
+		{"bad platform", `{"platform": "latest"}`, true},
+		{"unknown rule", `{"lint": {"no-such-rule": {"severity": "error"}}}`, true},
+		{"bad severity", `{"lint": {"unused-var": {"severity": "loud"}}}`, true},
+		{"negative indent", `{"format": {"indent": -1}}`, true},
+		{"unknown record", `{"records": {"punch": [{"name": "X", "type": "string"}]}}`, true},
+		{"bad field type", `{"records": {"exception": [{"name": "X", "type": "text"}]}}`, true},
+		{"not json", `platform = 9.2`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("have error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	opts := Format{LineWidth: 80}.Options()
+	if opts.LineWidth != 80 || opts.Indent != 4 {
+		t.Errorf("have %+v, want the default indent and a width of 80", opts)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	cfg, err := Parse([]byte(`{"records": {"timeRecord": [{"name": "DEPARTMENT", "type": "string"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := cfg.Schemas()
+	if _, ok := schemas.Field(types.T_TIMEREC, "DEPARTMENT"); !ok {
+		t.Error("DEPARTMENT not added to time records")
+	}
+	if _, ok := schemas.Field(types.T_TIMEREC, "HOURS"); !ok {
+		t.Error("HOURS missing from the default time record fields")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "formulas", "pay")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Discover(sub)
+	if err != nil || cfg.Lint != nil {
+		t.Fatalf("have %+v, %v, want the zero config without a file", cfg, err)
+	}
+
+	data := `{"lint": {"unused-var": {"severity": "off"}}, "catalogs": {"payCodes": ["codes.csv", "/abs/codes.csv"]}}`
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Discover(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Lint["unused-var"].Severity != lint.SeverityOff {
+		t.Errorf("have lint config %+v", cfg.Lint)
+	}
+	want := []string{filepath.Join(root, "codes.csv"), "/abs/codes.csv"}
+	if len(cfg.Catalogs.PayCodes) != 2 || cfg.Catalogs.PayCodes[0] != want[0] || cfg.Catalogs.PayCodes[1] != want[1] {
+		t.Errorf("have pay codes %v, want %v", cfg.Catalogs.PayCodes, want)
+	}
+}
+
+func TestCatalogsLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"codes.csv":   "code\nREG\n",
+		"attrs.json":  `[{"name": "UNION", "type": "string"}]`,
+		"macros.json": `[{"name": "GO_LIVE_DATE", "type": "date"}]`,
+		"macros.csv":  "name\nRULE\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cats := Catalogs{PayCodes: []string{"codes.csv"}, Attributes: []string{"attrs.json"}, Macros: []string{"macros.json", "macros.csv"}}
+	cats.resolve(dir)
+	cat, err := cats.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cat.PayCode("REG"); !ok {
+		t.Error("pay code REG not loaded")
+	}
+	if _, ok := cat.Attribute("UNION"); !ok {
+		t.Error("attribute UNION not loaded")
+	}
+	if macro, ok := cat.Macro("GO_LIVE_DATE"); !ok || macro.Type != types.T_DATE {
+		t.Errorf("have macro %+v, %t", macro, ok)
+	}
+	if _, ok := cat.Macro("RULE"); !ok {
+		t.Error("macro RULE not loaded")
+	}
+
+	cats.Macros = append(cats.Macros, filepath.Join(dir, "missing.json"))
+	if _, err := cats.Load(); err == nil {
+		t.Error("have no error for a missing macro library")
+	}
+}
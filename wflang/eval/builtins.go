@@ -0,0 +1,435 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// builtin evaluates a call to a builtin function. Arguments are evaluated by the
+// builtin itself, so that it can evaluate them lazily or repeatedly.
+type builtin func(e *evaluator, call ast.BuiltinCall) (object.Object, error)
+
+// builtins returns the builtin functions supported by the evaluator, keyed by
+// their lowercase name.
+func builtins() map[string]builtin {
+	return map[string]builtin{
+		object.If:       evalIf,
+		object.Min:      extreme(-1),
+		object.Max:      extreme(1),
+		object.Contains: stringFn(2, func(s []string) object.Object { return boolean(strings.Contains(s[0], s[1])) }),
+		object.IndexOf: stringFn(2, func(s []string) object.Object {
+			i := strings.Index(s[0], s[1])
+			if i < 0 {
+				return number(-1)
+			}
+			return number(float64(len([]rune(s[0][:i]))))
+		}),
+		object.ToLowerCase: stringFn(1, func(s []string) object.Object { return str(strings.ToLower(s[0])) }),
+		object.ToUpperCase: stringFn(1, func(s []string) object.Object { return str(strings.ToUpper(s[0])) }),
+		object.Substr:      evalSubstr,
+		object.MakeDate:    evalMakeDate,
+		object.Round:       rounding(math.Round),
+		object.RoundUp:     rounding(math.Ceil),
+		object.RoundDown:   rounding(math.Floor),
+		object.RoundToInt: func(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+			args, err := e.numberArgs(call, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return number(math.Round(args[0])), nil
+		},
+
+		object.SumTime:           summary(timeRecords, sum),
+		object.CountTime:         summary(timeRecords, count),
+		object.MinTime:           summary(timeRecords, minimum),
+		object.MaxTime:           summary(timeRecords, maximum),
+		object.AvgTime:           summary(timeRecords, average),
+		object.FindFirstTime:     summary(timeRecords, findFirst),
+		object.SumSchedule:       summary(schedules, sum),
+		object.CountSchedule:     summary(schedules, count),
+		object.MinSchedule:       summary(schedules, minimum),
+		object.MaxSchedule:       summary(schedules, maximum),
+		object.AvgSchedule:       summary(schedules, average),
+		object.FindFirstSchedule: summary(schedules, findFirst),
+		object.SumException:      summary(exceptions, sum),
+		object.CountException:    summary(exceptions, count),
+		object.MinException:      summary(exceptions, minimum),
+		object.MaxException:      summary(exceptions, maximum),
+		object.AvgException:      summary(exceptions, average),
+	}
+}
+
+// args evaluates the arguments of call, of which there must be between lo and hi
+// inclusive.
+func (e *evaluator) args(call ast.BuiltinCall, lo, hi int) ([]object.Object, error) {
+	if len(call.Args) < lo || len(call.Args) > hi {
+		if lo == hi {
+			return nil, newError(call, "%s: have %d arguments, want %d", call.Name, len(call.Args), lo)
+		}
+		return nil, newError(call, "%s: have %d arguments, want %d to %d", call.Name, len(call.Args), lo, hi)
+	}
+	objs := make([]object.Object, len(call.Args))
+	for i, arg := range call.Args {
+		obj, err := e.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		objs[i] = obj
+	}
+	return objs, nil
+}
+
+func (e *evaluator) numberArgs(call ast.BuiltinCall, lo, hi int) ([]float64, error) {
+	objs, err := e.args(call, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	nums := make([]float64, len(objs))
+	for i, obj := range objs {
+		if nums[i], err = asNumber(call.Args[i], obj); err != nil {
+			return nil, err
+		}
+	}
+	return nums, nil
+}
+
+// evalIf only evaluates the branch that is taken.
+func evalIf(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+	if len(call.Args) != 3 {
+		return nil, newError(call, "if: have %d arguments, want 3", len(call.Args))
+	}
+	cond, err := e.eval(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := asBool(call.Args[0], cond)
+	if err != nil {
+		return nil, err
+	}
+	if b {
+		return e.eval(call.Args[1])
+	}
+	return e.eval(call.Args[2])
+}
+
+// extreme returns min for sign -1, and max for sign 1.
+func extreme(sign float64) builtin {
+	return func(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+		nums, err := e.numberArgs(call, 1, math.MaxInt)
+		if err != nil {
+			return nil, err
+		}
+		result := nums[0]
+		for _, n := range nums[1:] {
+			if (n-result)*sign > 0 {
+				result = n
+			}
+		}
+		return number(result), nil
+	}
+}
+
+func stringFn(n int, fn func(s []string) object.Object) builtin {
+	return func(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+		objs, err := e.args(call, n, n)
+		if err != nil {
+			return nil, err
+		}
+		s := make([]string, n)
+		for i, obj := range objs {
+			if s[i], err = asString(call.Args[i], obj); err != nil {
+				return nil, err
+			}
+		}
+		return fn(s), nil
+	}
+}
+
+// evalSubstr takes a 1-indexed start. A start below 1 is treated as 1, and a length
+// beyond the end of the string is truncated.
+func evalSubstr(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+	objs, err := e.args(call, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+	s, err := asString(call.Args[0], objs[0])
+	if err != nil {
+		return nil, err
+	}
+	start, err := asNumber(call.Args[1], objs[1])
+	if err != nil {
+		return nil, err
+	}
+	length, err := asNumber(call.Args[2], objs[2])
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, newError(call.Args[2], "substr: negative length %v", length)
+	}
+
+	runes := []rune(s)
+	from := min(max(int(start), 1)-1, len(runes))
+	to := min(from+int(length), len(runes))
+	return str(string(runes[from:to])), nil
+}
+
+func evalMakeDate(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+	nums, err := e.numberArgs(call, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+	year, month, day := int(nums[0]), int(nums[1]), int(nums[2])
+	if month < 1 || month > 12 {
+		return nil, newError(call.Args[1], "makeDate: month %d out of range", month)
+	}
+	// day 0 of the month after is the last day of this one.
+	if last := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day(); day < 1 || day > last {
+		return nil, newError(call.Args[2], "makeDate: day %d out of range", day)
+	}
+	return object.Date{Val: time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), Static: true}, nil
+}
+
+// rounding returns a builtin that rounds its first argument to the number of
+// decimal places given by its optional second argument, using fn.
+func rounding(fn func(float64) float64) builtin {
+	return func(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+		nums, err := e.numberArgs(call, 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		scale := 1.0
+		if len(nums) == 2 {
+			scale = math.Pow(10, nums[1])
+		}
+		return number(fn(nums[0]*scale) / scale), nil
+	}
+}
+
+// recordSource returns the records a summary function iterates over.
+type recordSource func(ctx Context) []object.Record
+
+func timeRecords(ctx Context) []object.Record { return records(ctx.TimeRecords()) }
+func schedules(ctx Context) []object.Record   { return records(ctx.Schedules()) }
+func exceptions(ctx Context) []object.Record  { return records(ctx.Exceptions()) }
+
+func records[T object.Record](recs []T) []object.Record {
+	out := make([]object.Record, len(recs))
+	for i, rec := range recs {
+		out[i] = rec
+	}
+	return out
+}
+
+// summaryArgs are the arguments of a summary function call.
+type summaryArgs struct {
+	over    ast.OverExpression
+	where   ast.Expression // nil if there is no where clause.
+	orderBy *ast.OrderByExpression
+	expr    ast.Expression // nil if there is no expression.
+}
+
+// aggregate combines the records that qualify for a summary function.
+type aggregate func(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error)
+
+// summary returns a builtin that iterates over the records from src within the
+// over range of the call, which satisfy its where condition, and combines them
+// with agg.
+func summary(src recordSource, agg aggregate) builtin {
+	return func(e *evaluator, call ast.BuiltinCall) (object.Object, error) {
+		args, err := parseSummaryArgs(call)
+		if err != nil {
+			return nil, err
+		}
+		ctx, err := e.eval(args.over.Context)
+		if err != nil {
+			return nil, err
+		}
+		rng, ok := e.rangeOf(ctx)
+		if !ok {
+			return nil, newError(args.over.Context, "%s: cannot summarise over %s", call.Name, ctx.Type())
+		}
+
+		var recs []object.Record
+		for _, rec := range src(e.ctx) {
+			if !rng.contains(rec.WorkDate()) {
+				continue
+			}
+			if args.where != nil {
+				cond, err := e.within(args.over, rec, args.where)
+				if err != nil {
+					return nil, err
+				}
+				b, err := asBool(args.where, cond)
+				if err != nil {
+					return nil, err
+				}
+				if !b {
+					continue
+				}
+			}
+			recs = append(recs, rec)
+		}
+		return agg(e, call, args, recs)
+	}
+}
+
+func parseSummaryArgs(call ast.BuiltinCall) (summaryArgs, error) {
+	var (
+		args    summaryArgs
+		hasOver bool
+	)
+	for _, arg := range call.Args {
+		switch v := unwrap(arg).(type) {
+		case ast.OverExpression:
+			args.over, hasOver = v, true
+		case ast.WhereExpression:
+			args.where = v.Condition
+		case ast.OrderByExpression:
+			args.orderBy = &v
+		default:
+			if args.expr != nil {
+				return args, newError(arg, "%s: too many arguments", call.Name)
+			}
+			args.expr = arg
+		}
+	}
+	if !hasOver {
+		return args, newError(call, "%s: missing over clause", call.Name)
+	}
+	return args, nil
+}
+
+// unwrap returns the value of arg if it is a block that declares no vars.
+func unwrap(arg ast.Expression) ast.Expression {
+	if block, ok := arg.(ast.BlockExpression); ok && len(block.Vars) == 0 {
+		return block.Value
+	}
+	return arg
+}
+
+// within evaluates expr for the record rec, which is bound to the alias of over if
+// it has one, and whose fields can otherwise be referred to by name.
+func (e *evaluator) within(over ast.OverExpression, rec object.Record, expr ast.Expression) (object.Object, error) {
+	e.openScope()
+	e.records = append(e.records, rec)
+	defer func() {
+		e.closeScope()
+		e.records = e.records[:len(e.records)-1]
+	}()
+	if over.HasAlias {
+		e.declare(over.Alias.Alias.Value, rec)
+	}
+	return e.eval(expr)
+}
+
+// values evaluates the expression of a summary function for each record. Null
+// values are skipped.
+func (e *evaluator) values(call ast.BuiltinCall, args summaryArgs, recs []object.Record) ([]float64, error) {
+	if args.expr == nil {
+		return nil, newError(call, "%s: missing expression", call.Name)
+	}
+	nums := make([]float64, 0, len(recs))
+	for _, rec := range recs {
+		obj, err := e.within(args.over, rec, args.expr)
+		if err != nil {
+			return nil, err
+		}
+		if obj.Type() == types.T_NULL {
+			continue
+		}
+		n, err := asNumber(args.expr, obj)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+func count(_ *evaluator, _ ast.BuiltinCall, _ summaryArgs, recs []object.Record) (object.Object, error) {
+	return number(float64(len(recs))), nil
+}
+
+func sum(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error) {
+	nums, err := e.values(call, args, recs)
+	if err != nil {
+		return nil, err
+	}
+	var total float64
+	for _, n := range nums {
+		total += n
+	}
+	return number(total), nil
+}
+
+// average, minimum and maximum return null if no record qualifies.
+func average(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error) {
+	nums, err := e.values(call, args, recs)
+	if err != nil || len(nums) == 0 {
+		return object.Null{}, err
+	}
+	var total float64
+	for _, n := range nums {
+		total += n
+	}
+	return number(total / float64(len(nums))), nil
+}
+
+func minimum(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error) {
+	return summaryExtreme(e, call, args, recs, -1)
+}
+
+func maximum(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error) {
+	return summaryExtreme(e, call, args, recs, 1)
+}
+
+func summaryExtreme(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record, sign float64) (object.Object, error) {
+	nums, err := e.values(call, args, recs)
+	if err != nil || len(nums) == 0 {
+		return object.Null{}, err
+	}
+	result := nums[0]
+	for _, n := range nums[1:] {
+		if (n-result)*sign > 0 {
+			result = n
+		}
+	}
+	return number(result), nil
+}
+
+// findFirst returns the first record by the order by clause, ascending unless
+// desc is given, or null if no record qualifies.
+func findFirst(e *evaluator, call ast.BuiltinCall, args summaryArgs, recs []object.Record) (object.Object, error) {
+	if len(recs) == 0 {
+		return object.Null{}, nil
+	}
+	if args.orderBy == nil {
+		return recs[0], nil
+	}
+	desc := strings.EqualFold(args.orderBy.Ascending(), "desc")
+	first, firstKey := recs[0], object.Object(nil)
+	for _, rec := range recs {
+		key, err := e.within(args.over, rec, args.orderBy.Expression)
+		if err != nil {
+			return nil, err
+		}
+		if firstKey == nil {
+			first, firstKey = rec, key
+			continue
+		}
+		c, err := compare(args.orderBy, "order by", key, firstKey)
+		if err != nil {
+			return nil, err
+		}
+		if c < 0 && !desc || c > 0 && desc {
+			first, firstKey = rec, key
+		}
+	}
+	return first, nil
+}
@@ -0,0 +1,36 @@
+package eval
+
+import (
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// Context supplies the data a formula is evaluated against. Summary functions
+// iterate over the records it returns that fall within their over range.
+type Context interface {
+	Day() time.Time                 // the day being evaluated.
+	Week() (start, end time.Time)   // the week containing Day.
+	Period() (start, end time.Time) // the pay period containing Day.
+	TimeRecords() []object.TimeRecord
+	Schedules() []object.ScheduleRecord
+	Exceptions() []object.Exception
+}
+
+// dateRange is an inclusive range of days.
+type dateRange struct {
+	start, end time.Time
+}
+
+func newDateRange(start, end time.Time) dateRange {
+	return dateRange{start: truncDay(start), end: truncDay(end)}
+}
+
+func (d dateRange) contains(t time.Time) bool {
+	t = truncDay(t)
+	return !t.Before(d.start) && !t.After(d.end)
+}
+
+func truncDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
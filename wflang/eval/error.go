@@ -0,0 +1,28 @@
+package eval
+
+import (
+	"fmt"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// Error is a runtime error raised while evaluating a formula, such as a division
+// by zero or a call to a builtin that the evaluator does not support.
+type Error struct {
+	Msg  string
+	Node ast.Node // the node being evaluated when the error was raised.
+}
+
+func newError(node ast.Node, format string, a ...any) Error {
+	return Error{Msg: fmt.Sprintf(format, a...), Node: node}
+}
+
+// Error returns the message prefixed with the 1-based line and column of the node.
+func (e Error) Error() string {
+	start, _ := e.Node.Pos()
+	return fmt.Sprintf("%d:%d: %s", start.Line+1, start.Col+1, e.Msg)
+}
+
+// Pos returns the StartPos and EndPos of the node at which the error was raised.
+func (e Error) Pos() (start, end token.Pos) { return e.Node.Pos() }
@@ -0,0 +1,493 @@
+// Package eval executes WFLang formulas, computing the value of an AST against the
+// data supplied by a Context. It is intended for trying formulas out locally, so
+// covers the core of the language rather than every builtin.
+package eval
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// Eval evaluates the formula root against ctx, and returns the value of its last
+// expression. root must be free of parse errors.
+func Eval(root *ast.AST, ctx Context) (object.Object, error) {
+	e := &evaluator{ctx: ctx, scopes: []map[string]object.Object{{}}}
+	var result object.Object = object.Null{}
+	for _, stmt := range root.Statements {
+		obj, err := e.eval(stmt)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := stmt.(ast.ExpressionStatement); ok {
+			result = obj
+		}
+	}
+	return result, nil
+}
+
+type evaluator struct {
+	ctx     Context
+	scopes  []map[string]object.Object
+	records []object.Record // records being iterated by the enclosing summary functions, innermost last.
+}
+
+func (e *evaluator) eval(n ast.Node) (object.Object, error) {
+	switch v := n.(type) {
+	case ast.ExpressionStatement:
+		return e.eval(v.Expression)
+
+	case ast.VarStatement:
+		val, err := e.eval(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		e.declare(v.Name.Value, val)
+		return val, nil
+
+	case ast.LineCommentStatement, ast.BlockCommentStatement:
+		return object.Null{}, nil
+
+	case ast.Ident:
+		obj, ok := e.lookup(v.Value)
+		if !ok {
+			return nil, newError(v, "undefined: %s", v.Value)
+		}
+		return obj, nil
+
+	case ast.NumberLiteral:
+		return number(v.Val), nil
+	case ast.StringLiteral:
+		return str(unquote(v.Literal)), nil
+	case ast.BooleanLiteral:
+		return boolean(v.Value), nil
+	case ast.NullLiteral:
+		return object.Null{}, nil
+	case ast.DateLiteral:
+		return object.Date{Val: v.Time, Static: true}, nil
+	case ast.TimeLiteral:
+		return object.Time{Val: v.Time, Static: true}, nil
+
+	case ast.PrefixExpression:
+		return e.evalPrefix(v)
+	case ast.InfixExpression:
+		return e.evalInfix(v)
+	case ast.InExpression:
+		return e.evalIn(v)
+
+	case ast.ParenExpression:
+		return e.eval(v.Inner)
+
+	case ast.BlockExpression:
+		if len(v.Vars) > 0 {
+			e.openScope()
+			defer e.closeScope()
+		}
+		for _, vs := range v.Vars {
+			if _, err := e.eval(vs); err != nil {
+				return nil, err
+			}
+		}
+		return e.eval(v.Value)
+
+	case ast.MemberExpression:
+		return e.evalMember(v)
+
+	case ast.BuiltinCall:
+		fn, ok := builtins()[v.Name]
+		if !ok {
+			return nil, newError(v, "%s: builtin is not supported by the evaluator", v.Name)
+		}
+		return fn(e, v)
+
+	case ast.MacroExpression:
+		return nil, newError(v, "macros cannot be evaluated")
+
+	case ast.ParseErr:
+		return nil, newError(v, "cannot evaluate a formula with parse errors: %s", v.Msg)
+	}
+	return nil, newError(n, "cannot evaluate %T", n)
+}
+
+func (e *evaluator) evalPrefix(v ast.PrefixExpression) (object.Object, error) {
+	right, err := e.eval(v.Right)
+	if err != nil {
+		return nil, err
+	}
+	switch v.Token.Type {
+	case token.T_MINUS:
+		n, err := asNumber(v.Right, right)
+		if err != nil {
+			return nil, err
+		}
+		return number(-n), nil
+	case token.T_BANG:
+		b, err := asBool(v.Right, right)
+		if err != nil {
+			return nil, err
+		}
+		return boolean(!b), nil
+	}
+	return nil, newError(v, "unknown operator %s", v.Prefix)
+}
+
+func (e *evaluator) evalInfix(v ast.InfixExpression) (object.Object, error) {
+	left, err := e.eval(v.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators short-circuit, so the right operand is not always evaluated.
+	if v.Token.Type == token.T_AND || v.Token.Type == token.T_OR {
+		l, err := asBool(v.Left, left)
+		if err != nil {
+			return nil, err
+		}
+		if l == (v.Token.Type == token.T_OR) {
+			return boolean(l), nil
+		}
+		right, err := e.eval(v.Right)
+		if err != nil {
+			return nil, err
+		}
+		r, err := asBool(v.Right, right)
+		if err != nil {
+			return nil, err
+		}
+		return boolean(r), nil
+	}
+
+	right, err := e.eval(v.Right)
+	if err != nil {
+		return nil, err
+	}
+	switch v.Token.Type {
+	case token.T_EQ, token.T_NEQ:
+		eq, err := equal(v, v.Infix, left, right)
+		if err != nil {
+			return nil, err
+		}
+		return boolean(eq == (v.Token.Type == token.T_EQ)), nil
+
+	case token.T_LT, token.T_GT, token.T_LTE, token.T_GTE:
+		c, err := compare(v, v.Infix, left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch v.Token.Type {
+		case token.T_LT:
+			return boolean(c < 0), nil
+		case token.T_GT:
+			return boolean(c > 0), nil
+		case token.T_LTE:
+			return boolean(c <= 0), nil
+		}
+		return boolean(c >= 0), nil
+
+	case token.T_PLUS, token.T_MINUS, token.T_ASTERISK, token.T_SLASH, token.T_MODULO:
+		return arithmetic(v, left, right)
+	}
+	return nil, newError(v, "unknown operator %s", v.Infix)
+}
+
+// arithmetic applies the arithmetic operator of v to left and right. Numbers of
+// days can be added to or subtracted from dates and dateTimes, and the difference
+// between two of the same is a number of days.
+func arithmetic(v ast.InfixExpression, left, right object.Object) (object.Object, error) {
+	if left.Type() == types.T_NUMBER && right.Type() == types.T_NUMBER {
+		l, _ := asNumber(v.Left, left)
+		r, _ := asNumber(v.Right, right)
+		switch v.Token.Type {
+		case token.T_PLUS:
+			return number(l + r), nil
+		case token.T_MINUS:
+			return number(l - r), nil
+		case token.T_ASTERISK:
+			return number(l * r), nil
+		case token.T_SLASH:
+			if r == 0 {
+				return nil, newError(v, "division by zero")
+			}
+			return number(l / r), nil
+		case token.T_MODULO:
+			if r == 0 {
+				return nil, newError(v, "division by zero")
+			}
+			return number(math.Mod(l, r)), nil
+		}
+	}
+
+	switch {
+	case v.Token.Type == token.T_PLUS && left.Type() == types.T_STRING && right.Type() == types.T_STRING:
+		l, _ := asString(v.Left, left)
+		r, _ := asString(v.Right, right)
+		return str(l + r), nil
+
+	case (v.Token.Type == token.T_PLUS || v.Token.Type == token.T_MINUS) && right.Type() == types.T_NUMBER:
+		days, _ := asNumber(v.Right, right)
+		if v.Token.Type == token.T_MINUS {
+			days = -days
+		}
+		switch l := left.(type) {
+		case object.Date:
+			return object.Date{Val: l.Val.AddDate(0, 0, int(days)), Static: true}, nil
+		case object.DateTime:
+			return object.DateTime{Val: l.Val.Add(time.Duration(days * float64(24*time.Hour))), Static: true}, nil
+		}
+
+	case v.Token.Type == token.T_MINUS && left.Type() == right.Type():
+		switch l := left.(type) {
+		case object.Date:
+			return number(l.Val.Sub(right.(object.Date).Val).Hours() / 24), nil
+		case object.DateTime:
+			return number(l.Val.Sub(right.(object.DateTime).Val).Hours() / 24), nil
+		}
+	}
+	return nil, newError(v, "operator %s is not supported for %s and %s", v.Infix, left.Type(), right.Type())
+}
+
+// equal returns true if left and right hold the same value. Null is only equal to
+// null. Errors are reported against node n, for the operator op.
+func equal(n ast.Node, op string, left, right object.Object) (bool, error) {
+	lNull, rNull := left.Type() == types.T_NULL, right.Type() == types.T_NULL
+	if lNull || rNull {
+		return lNull && rNull, nil
+	}
+	if left.Type() != right.Type() {
+		return false, newError(n, "operator %s: mismatched types %s and %s", op, left.Type(), right.Type())
+	}
+	switch left.Type() {
+	case types.T_NUMBER, types.T_STRING, types.T_BOOL, types.T_IDENT:
+		l, _ := left.Value()
+		r, _ := right.Value()
+		return l == r, nil
+	case types.T_DATE, types.T_TIME, types.T_DTTM:
+		c, err := compare(n, op, left, right)
+		return c == 0, err
+	}
+	return false, newError(n, "operator %s: type %s cannot be compared", op, left.Type())
+}
+
+// compare returns -1, 0 or 1 if left is less than, equal to or greater than right.
+// Errors are reported against node n, for the operator op.
+func compare(n ast.Node, op string, left, right object.Object) (int, error) {
+	if left.Type() != right.Type() {
+		return 0, newError(n, "operator %s: mismatched types %s and %s", op, left.Type(), right.Type())
+	}
+	switch left.Type() {
+	case types.T_NUMBER:
+		l, _ := asNumber(n, left)
+		r, _ := asNumber(n, right)
+		return cmpFloat(l, r), nil
+	case types.T_STRING:
+		l, _ := asString(n, left)
+		r, _ := asString(n, right)
+		return strings.Compare(l, r), nil
+	case types.T_DATE, types.T_TIME, types.T_DTTM:
+		l, _ := left.Value()
+		r, _ := right.Value()
+		return l.(time.Time).Compare(r.(time.Time)), nil
+	}
+	return 0, newError(n, "operator %s: type %s is not ordered", op, left.Type())
+}
+
+func cmpFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func (e *evaluator) evalIn(v ast.InExpression) (object.Object, error) {
+	left, err := e.eval(v.Left)
+	if err != nil {
+		return nil, err
+	}
+	s, err := asString(v.Left, left)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := v.List.(ast.ListLiteral)
+	if !ok {
+		return nil, newError(v.List, "policy sets cannot be evaluated")
+	}
+	for _, item := range list.Strings {
+		if unquote(item.Literal) == s {
+			return boolean(true), nil
+		}
+	}
+	return boolean(false), nil
+}
+
+// evalMember resolves the fields and methods of records, and the start and end of
+// date ranges and the summary contexts day, week and period.
+func (e *evaluator) evalMember(v ast.MemberExpression) (object.Object, error) {
+	obj, err := e.eval(v.Object)
+	if err != nil {
+		return nil, err
+	}
+	name := v.Member.Value
+	if v.Call {
+		return e.callMethod(v, obj)
+	}
+	if rec, ok := obj.(object.Record); ok {
+		field, ok := rec.Field(name)
+		if !ok {
+			return nil, newError(v.Member, "%s has no field %s", rec.Type(), name)
+		}
+		return field, nil
+	}
+
+	if obj.Type() == types.T_NULL {
+		return nil, newError(v, "cannot access %s of null", name)
+	}
+	rng, ok := e.rangeOf(obj)
+	if !ok {
+		return nil, newError(v, "cannot access %s of %s", name, obj.Type())
+	}
+	switch strings.ToLower(name) {
+	case "start":
+		return object.Date{Val: rng.start, Static: true}, nil
+	case "end":
+		return object.Date{Val: rng.end, Static: true}, nil
+	}
+	return nil, newError(v.Member, "%s has no field %s", obj.Type(), name)
+}
+
+// callMethod calls the method of the record obj named by v.
+func (e *evaluator) callMethod(v ast.MemberExpression, obj object.Object) (object.Object, error) {
+	name := v.Member.Value
+	rec, ok := obj.(object.Record)
+	if !ok {
+		return nil, newError(v, "cannot call %s of %s", name, obj.Type())
+	}
+	fn, ok := object.RecordMethod(rec.Type(), name)
+	if !ok {
+		return nil, newError(v.Member, "%s has no method %s", rec.Type(), name)
+	}
+	if len(v.Args) != len(fn.Params) {
+		return nil, newError(v, "%s: have %d arguments, want %d", fn.Name, len(v.Args), len(fn.Params))
+	}
+	args := make([]string, len(v.Args)) // the params of all methods are strings.
+	for i, arg := range v.Args {
+		val, err := e.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := val.(object.String)
+		if !ok {
+			return nil, newError(arg, "%s: argument %s: have %s, want string", fn.Name, fn.Params[i].Name, val.Type())
+		}
+		args[i] = s.Val
+	}
+
+	field := func(name string) object.Object {
+		obj, _ := rec.Field(name)
+		return obj
+	}
+	switch fn.Name {
+	case object.MethodHasTimes:
+		return boolean(field(object.FieldStartDttm).Type() != types.T_NULL && field(object.FieldEndDttm).Type() != types.T_NULL), nil
+	case object.MethodTimeRange:
+		start, hasStart := field(object.FieldStartDttm).(object.DateTime)
+		end, hasEnd := field(object.FieldEndDttm).(object.DateTime)
+		if !hasStart || !hasEnd {
+			return object.Null{}, nil
+		}
+		rng := object.DateTimeRange{Static: true}
+		rng.Val.Start, rng.Val.End = start.Val, end.Val
+		return rng, nil
+	case object.MethodIsSeverity:
+		return boolean(strings.EqualFold(field(object.FieldSeverity).(object.String).Val, args[0])), nil
+	case object.MethodHasStatus:
+		return boolean(strings.EqualFold(field(object.FieldStatus).(object.String).Val, args[0])), nil
+	}
+	return nil, newError(v.Member, "%s: method is not supported by the evaluator", fn.Name)
+}
+
+// rangeOf returns the range of days covered by obj, which can be a summary context,
+// a date or a dateRange.
+func (e *evaluator) rangeOf(obj object.Object) (dateRange, bool) {
+	switch o := obj.(type) {
+	case object.Day:
+		return newDateRange(e.ctx.Day(), e.ctx.Day()), true
+	case object.Week:
+		return newDateRange(e.ctx.Week()), true
+	case object.Period:
+		return newDateRange(e.ctx.Period()), true
+	case object.Date:
+		return newDateRange(o.Val, o.Val), true
+	case object.DateRange:
+		return newDateRange(o.Val.Start, o.Val.End), true
+	}
+	return dateRange{}, false
+}
+
+// lookup resolves name to a declared variable, a field of the record being
+// iterated, or a summary context, in that order.
+func (e *evaluator) lookup(name string) (object.Object, bool) {
+	for i := len(e.scopes) - 1; i >= 0; i-- {
+		if obj, ok := e.scopes[i][strings.ToLower(name)]; ok {
+			return obj, true
+		}
+	}
+	if len(e.records) > 0 {
+		if obj, ok := e.records[len(e.records)-1].Field(name); ok {
+			return obj, true
+		}
+	}
+	switch strings.ToLower(name) {
+	case "day":
+		return object.Day{}, true
+	case "week":
+		return object.Week{}, true
+	case "period":
+		return object.Period{}, true
+	}
+	return nil, false
+}
+
+func (e *evaluator) declare(name string, obj object.Object) {
+	e.scopes[len(e.scopes)-1][strings.ToLower(name)] = obj
+}
+
+func (e *evaluator) openScope()  { e.scopes = append(e.scopes, map[string]object.Object{}) }
+func (e *evaluator) closeScope() { e.scopes = e.scopes[:len(e.scopes)-1] }
+
+func number(n float64) object.Number { return object.Number{Val: n, Static: true} }
+func str(s string) object.String     { return object.String{Val: s, Static: true} }
+func boolean(b bool) object.Boolean  { return object.Boolean{Val: b, Static: true} }
+
+// unquote returns the value of a string literal, without its quotes.
+func unquote(lit string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(lit, `"`), `"`)
+}
+
+func asNumber(n ast.Node, obj object.Object) (float64, error) {
+	if v, ok := obj.(object.Number); ok {
+		return v.Val, nil
+	}
+	return 0, newError(n, "have %s, want %s", obj.Type(), types.T_NUMBER)
+}
+
+func asString(n ast.Node, obj object.Object) (string, error) {
+	if v, ok := obj.(object.String); ok {
+		return v.Val, nil
+	}
+	return "", newError(n, "have %s, want %s", obj.Type(), types.T_STRING)
+}
+
+func asBool(n ast.Node, obj object.Object) (bool, error) {
+	if v, ok := obj.(object.Boolean); ok {
+		return v.Val, nil
+	}
+	return false, newError(n, "have %s, want %s", obj.Type(), types.T_BOOL)
+}
@@ -0,0 +1,168 @@
+package eval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scatternoodle/wflang/testhelp"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func date(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+type testContext struct{}
+
+func (testContext) Day() time.Time                 { return date(2024, 1, 10) }
+func (testContext) Week() (start, end time.Time)   { return date(2024, 1, 8), date(2024, 1, 14) }
+func (testContext) Period() (start, end time.Time) { return date(2024, 1, 1), date(2024, 1, 14) }
+
+func (testContext) TimeRecords() []object.TimeRecord {
+	return []object.TimeRecord{
+		{Date: date(2024, 1, 10), Hours: 8, PayCode: "REG"},
+		{Date: date(2024, 1, 10), Hours: 2, PayCode: "OT"},
+		{Date: date(2024, 1, 9), Hours: 7.5, PayCode: "REG"},
+		{Date: date(2024, 1, 20), Hours: 8, PayCode: "REG"}, // outside the period
+	}
+}
+
+func (testContext) Schedules() []object.ScheduleRecord {
+	return []object.ScheduleRecord{
+		{Date: date(2024, 1, 10), Hours: 8, PayCode: "REG"},
+		{Date: date(2024, 1, 11), Hours: 8, PayCode: "REG", Start: date(2024, 1, 11).Add(9 * time.Hour), End: date(2024, 1, 11).Add(17 * time.Hour)},
+	}
+}
+
+func (testContext) Exceptions() []object.Exception {
+	return []object.Exception{{Date: date(2024, 1, 9), Severity: "HIGH", Fields: map[string]object.Object{"REASON": str("late")}}}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantType types.Type
+		want     any
+	}{
+		{`1 + 2 * 3`, types.T_NUMBER, 7.0},
+		{`(1 + 2) * 3`, types.T_NUMBER, 9.0},
+		{`10 % 3`, types.T_NUMBER, 1.0},
+		{`-(2)`, types.T_NUMBER, -2.0},
+		{`"a" + "b"`, types.T_STRING, "ab"},
+		{`true && !false`, types.T_BOOL, true},
+		{`1 < 2 or 1 / 0 > 1`, types.T_BOOL, true},
+		{`"a" = "a" and 1 != 2`, types.T_BOOL, true},
+		{`null = null and 1 != null`, types.T_BOOL, true},
+		{`"b" in ["a", "b"]`, types.T_BOOL, true},
+		{`if(1 > 2, "a", "b")`, types.T_STRING, "b"},
+		{`if(true, 1, 1 / 0)`, types.T_NUMBER, 1.0},
+		{`min(3, 1, 2)`, types.T_NUMBER, 1.0},
+		{`max(3, 1, 2)`, types.T_NUMBER, 3.0},
+		{`contains("abc", "b")`, types.T_BOOL, true},
+		{`indexOf("abc", "c")`, types.T_NUMBER, 2.0},
+		{`indexOf("abc", "d")`, types.T_NUMBER, -1.0},
+		{`substr("ABCDEF", 2, 3)`, types.T_STRING, "BCD"},
+		{`substr("ABC", 0, 10)`, types.T_STRING, "ABC"},
+		{`toUpperCase("ab") + toLowerCase("CD")`, types.T_STRING, "ABcd"},
+		{`makeDate(2024, 2, 29)`, types.T_DATE, date(2024, 2, 29)},
+		{`{2024-01-31} + 1`, types.T_DATE, date(2024, 2, 1)},
+		{`makeDate(2024, 1, 10) - {2024-01-01}`, types.T_NUMBER, 9.0},
+		{`{2024-01-01} < {2024-01-02}`, types.T_BOOL, true},
+		{`round(2.5)`, types.T_NUMBER, 3.0},
+		{`round(1.234, 2)`, types.T_NUMBER, 1.23},
+		{`roundUp(1.21, 1)`, types.T_NUMBER, 1.3},
+		{`roundDown(1.29, 1)`, types.T_NUMBER, 1.2},
+		{`roundToInt(2.4)`, types.T_NUMBER, 2.0},
+		{"var x = 2;\nvar y = (var x = 10; x + 1);\nx + y", types.T_NUMBER, 13.0},
+		{`period.end`, types.T_DATE, date(2024, 1, 14)},
+
+		{`sumTime(over day alias t, t.HOURS)`, types.T_NUMBER, 10.0},
+		{`sumTime(over period, HOURS, where PAY_CODE = "REG")`, types.T_NUMBER, 15.5},
+		{`countTime(over week alias t, where t.hours > 5)`, types.T_NUMBER, 2.0},
+		{`avgTime(over day alias t, t.HOURS)`, types.T_NUMBER, 5.0},
+		{`maxTime(over period alias t, t.HOURS)`, types.T_NUMBER, 8.0},
+		{`avgTime(over week alias t, t.HOURS, where t.HOURS > 100)`, types.T_NULL, nil},
+		{`findFirstTime(over period alias t, where t.HOURS > 0, order by t.HOURS).PAY_CODE`, types.T_STRING, "OT"},
+		{`findFirstTime(over period alias t, where t.HOURS > 0, order by t.HOURS desc).HOURS`, types.T_NUMBER, 8.0},
+		{`findFirstTime(over period alias t, where t.HOURS > 100, order by t.HOURS)`, types.T_NULL, nil},
+		{`minSchedule(over day alias s, s.HOURS)`, types.T_NUMBER, 8.0},
+		{`countSchedule(over period)`, types.T_NUMBER, 2.0},
+		{`countException(over week alias e, where e.SEVERITY = "HIGH")`, types.T_NUMBER, 1.0},
+		{`countException(over week alias e, where e.reason = "late")`, types.T_NUMBER, 1.0},
+		{`findFirstTime(over day alias t, where true).START_DTTM`, types.T_NULL, nil},
+		{`sumTime(over day alias t, t.HOURS * countSchedule(over period))`, types.T_NUMBER, 20.0},
+		{`countSchedule(over period alias s, where s.hasTimes())`, types.T_NUMBER, 1.0},
+		{`findFirstTime(over day alias t, where true).hasTimes()`, types.T_BOOL, false},
+		{`findFirstTime(over day alias t, where true).timeRange()`, types.T_NULL, nil},
+		{`countSchedule(over period alias s, where s.timeRange() != null)`, types.T_NUMBER, 1.0},
+		{`countException(over week alias e, where e.isSeverity("high"))`, types.T_NUMBER, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			obj, err := testEval(t, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obj.Type() != tt.wantType {
+				t.Fatalf("type: have %s, want %s", obj.Type(), tt.wantType)
+			}
+			if tt.want == nil {
+				return
+			}
+			have, ok := obj.Value()
+			if !ok {
+				t.Fatalf("value of %+v is not static", obj)
+			}
+			if have != tt.want {
+				t.Errorf("value: have %v, want %v", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantCol uint // start column of the node that raised the error
+	}{
+		{`1 / 0`, 0},
+		{`1 + x`, 4},
+		{`"a" + 1`, 0},
+		{`makeDate(2024, 13, 1)`, 15},
+		{`makeDate(2024, 2, 30)`, 18},
+		{`makeDate(2023, 2, 29)`, 18},
+		{`makeDate(2024, 4, 31)`, 18},
+		{`substr("abc", 1, -1)`, 17},
+		{`employee_attribute(ATTR)`, 0},
+		{`sumTime(over day alias t, t.MISSING)`, 28},
+		{`sumTime(where true)`, 0},
+		{`sumTime(over day alias t, t.isSeverity("HIGH"))`, 28},
+		{`countException(over week alias e, where e.isSeverity(1))`, 53},
+		{`period.end()`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := testEval(t, tt.input)
+			evalErr := testhelp.AssertType[Error](t, err)
+			if start, _ := evalErr.Pos(); start.Col != tt.wantCol {
+				t.Errorf("error col: have %d, want %d (%s)", start.Col, tt.wantCol, err)
+			}
+		})
+	}
+}
+
+func testEval(t *testing.T, input string) (object.Object, error) {
+	t.Helper()
+	prs := parser.New(lexer.New(input))
+	root, err := prs.AST()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs.Errors()) > 0 {
+		t.Fatalf("parse errors: %v", prs.Errors())
+	}
+	return Eval(root, testContext{})
+}
@@ -0,0 +1,34 @@
+package fixture
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/eval"
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// Context returns an eval.Context for evaluating formulas on day, which must fall
+// within one of the fixture's pay periods.
+func (f *Fixture) Context(day time.Time) (eval.Context, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	period, ok := f.Period(day)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in any pay period of the fixture", day.Format(dateLayout))
+	}
+	return dayContext{fixture: f, day: day, period: period}, nil
+}
+
+// dayContext is the context of a single day of a fixture.
+type dayContext struct {
+	fixture *Fixture
+	day     time.Time
+	period  Period
+}
+
+func (c dayContext) Day() time.Time                     { return c.day }
+func (c dayContext) Week() (start, end time.Time)       { return c.fixture.Week(c.day) }
+func (c dayContext) Period() (start, end time.Time)     { return c.period.Start, c.period.End }
+func (c dayContext) TimeRecords() []object.TimeRecord   { return c.fixture.TimeRecords }
+func (c dayContext) Schedules() []object.ScheduleRecord { return c.fixture.Schedules }
+func (c dayContext) Exceptions() []object.Exception     { return c.fixture.Exceptions }
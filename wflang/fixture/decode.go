@@ -0,0 +1,338 @@
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
+// The JSON encoding of a fixture. Values are validated and converted to objects
+// by build.
+type (
+	fixtureJSON struct {
+		Employee   string          `json:"employee"`
+		Calendar   calendarJSON    `json:"calendar"`
+		Attributes []attributeJSON `json:"attributes"`
+		Days       []dayJSON       `json:"days"`
+	}
+
+	calendarJSON struct {
+		WeekStart string       `json:"weekStart"`
+		Periods   []periodJSON `json:"periods"`
+	}
+
+	periodJSON struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+
+	attributeJSON struct {
+		Name  string          `json:"name"`
+		Type  types.Type      `json:"type"`
+		Value json.RawMessage `json:"value"`
+		Start string          `json:"start"`
+		End   string          `json:"end"`
+	}
+
+	dayJSON struct {
+		Date       string          `json:"date"`
+		Time       []recordJSON    `json:"time"`
+		Schedule   []recordJSON    `json:"schedule"`
+		Exceptions []exceptionJSON `json:"exceptions"`
+		TOR        []torJSON       `json:"tor"`
+	}
+
+	recordJSON struct {
+		PayCode  string         `json:"payCode"`
+		Hours    *float64       `json:"hours"` // if nil, the time between Start and End.
+		Start    string         `json:"start"`
+		End      string         `json:"end"`
+		WorkRule string         `json:"workRule"`
+		Fields   map[string]any `json:"fields"`
+	}
+
+	exceptionJSON struct {
+		Code     string         `json:"code"`
+		Severity string         `json:"severity"`
+		Message  string         `json:"message"`
+		Fields   map[string]any `json:"fields"`
+	}
+
+	torJSON struct {
+		ID      string         `json:"id"`
+		PayCode string         `json:"payCode"`
+		Hours   float64        `json:"hours"`
+		Status  string         `json:"status"`
+		Fields  map[string]any `json:"fields"`
+	}
+)
+
+func (raw fixtureJSON) build() (*Fixture, error) {
+	f := &Fixture{Employee: raw.Employee}
+
+	var err error
+	if f.WeekStart, err = parseWeekday(raw.Calendar.WeekStart); err != nil {
+		return nil, Error{Path: "calendar.weekStart", Msg: err.Error()}
+	}
+	if len(raw.Calendar.Periods) == 0 {
+		return nil, Error{Path: "calendar.periods", Msg: "at least one pay period is required"}
+	}
+	for i, p := range raw.Calendar.Periods {
+		period, err := p.build(fmt.Sprintf("calendar.periods[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		f.Periods = append(f.Periods, period)
+	}
+	slices.SortFunc(f.Periods, func(a, b Period) int { return a.Start.Compare(b.Start) })
+	for i := 1; i < len(f.Periods); i++ {
+		if !f.Periods[i].Start.After(f.Periods[i-1].End) {
+			return nil, Error{Path: "calendar.periods", Msg: fmt.Sprintf("pay periods starting %s and %s overlap",
+				f.Periods[i-1].Start.Format(dateLayout), f.Periods[i].Start.Format(dateLayout))}
+		}
+	}
+
+	for i, a := range raw.Attributes {
+		attr, err := a.build(fmt.Sprintf("attributes[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		f.Attributes = append(f.Attributes, attr)
+	}
+
+	seen := map[string]bool{}
+	for i, d := range raw.Days {
+		path := fmt.Sprintf("days[%d]", i)
+		if seen[d.Date] {
+			return nil, Error{Path: path + ".date", Msg: fmt.Sprintf("duplicate day %s", d.Date)}
+		}
+		seen[d.Date] = true
+		if err := d.build(path, f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p periodJSON) build(path string) (Period, error) {
+	start, err := parseDate(path+".start", p.Start)
+	if err != nil {
+		return Period{}, err
+	}
+	end, err := parseDate(path+".end", p.End)
+	if err != nil {
+		return Period{}, err
+	}
+	if end.Before(start) {
+		return Period{}, Error{Path: path, Msg: "end is before start"}
+	}
+	return Period{Start: start, End: end}, nil
+}
+
+func (a attributeJSON) build(path string) (object.Attribute, error) {
+	attr := object.Attribute{Name: a.Name, AttType: a.Type}
+	if a.Name == "" {
+		return attr, Error{Path: path + ".name", Msg: "name is required"}
+	}
+	var err error
+	if attr.Val, err = attributeValue(a.Type, a.Value); err != nil {
+		return attr, Error{Path: path + ".value", Msg: err.Error()}
+	}
+	if a.Start != "" {
+		if attr.Start, err = parseDate(path+".start", a.Start); err != nil {
+			return attr, err
+		}
+	}
+	if a.End != "" {
+		if attr.End, err = parseDate(path+".end", a.End); err != nil {
+			return attr, err
+		}
+	}
+	if !attr.Start.IsZero() && !attr.End.IsZero() && attr.End.Before(attr.Start) {
+		return attr, Error{Path: path, Msg: "end is before start"}
+	}
+	return attr, nil
+}
+
+func (d dayJSON) build(path string, f *Fixture) error {
+	date, err := parseDate(path+".date", d.Date)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range d.Time {
+		rec, err := r.build(fmt.Sprintf("%s.time[%d]", path, i), date)
+		if err != nil {
+			return err
+		}
+		f.TimeRecords = append(f.TimeRecords, object.TimeRecord(rec))
+	}
+	for i, r := range d.Schedule {
+		rec, err := r.build(fmt.Sprintf("%s.schedule[%d]", path, i), date)
+		if err != nil {
+			return err
+		}
+		f.Schedules = append(f.Schedules, object.ScheduleRecord(rec))
+	}
+	for i, e := range d.Exceptions {
+		epath := fmt.Sprintf("%s.exceptions[%d]", path, i)
+		if e.Code == "" {
+			return Error{Path: epath + ".code", Msg: "code is required"}
+		}
+		fields, err := customFields(epath+".fields", e.Fields)
+		if err != nil {
+			return err
+		}
+		f.Exceptions = append(f.Exceptions, object.Exception{
+			Date: date, Code: e.Code, Severity: e.Severity, Message: e.Message, Fields: fields,
+		})
+	}
+	for i, t := range d.TOR {
+		tpath := fmt.Sprintf("%s.tor[%d]", path, i)
+		if t.ID == "" {
+			return Error{Path: tpath + ".id", Msg: "id is required"}
+		}
+		fields, err := customFields(tpath+".fields", t.Fields)
+		if err != nil {
+			return err
+		}
+		f.TORDetails = append(f.TORDetails, object.TORDetailRecord{
+			TorID: t.ID, Date: date, PayCode: t.PayCode, Hours: t.Hours, Status: t.Status, Fields: fields,
+		})
+	}
+	return nil
+}
+
+// build returns a time record, which has the same fields as a schedule record.
+func (r recordJSON) build(path string, date time.Time) (object.TimeRecord, error) {
+	rec := object.TimeRecord{Date: date, PayCode: r.PayCode, WorkRule: r.WorkRule}
+	if r.PayCode == "" {
+		return rec, Error{Path: path + ".payCode", Msg: "payCode is required"}
+	}
+
+	var err error
+	if r.Start != "" {
+		if rec.Start, err = parseTime(path+".start", date, r.Start); err != nil {
+			return rec, err
+		}
+	}
+	if r.End != "" {
+		if rec.End, err = parseTime(path+".end", date, r.End); err != nil {
+			return rec, err
+		}
+		// A record that ends before it starts runs overnight.
+		if !rec.Start.IsZero() && !rec.End.After(rec.Start) {
+			rec.End = rec.End.AddDate(0, 0, 1)
+		}
+	}
+
+	switch {
+	case r.Hours != nil:
+		rec.Hours = *r.Hours
+	case !rec.Start.IsZero() && !rec.End.IsZero():
+		rec.Hours = rec.End.Sub(rec.Start).Hours()
+	default:
+		return rec, Error{Path: path + ".hours", Msg: "hours are required unless start and end are given"}
+	}
+
+	rec.Fields, err = customFields(path+".fields", r.Fields)
+	return rec, err
+}
+
+// customFields converts the custom fields of a record to objects. Only strings,
+// numbers, booleans and null are allowed.
+func customFields(path string, raw map[string]any) (map[string]object.Object, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]object.Object, len(raw))
+	for name, v := range raw {
+		switch val := v.(type) {
+		case nil:
+			fields[name] = object.Null{}
+		case string:
+			fields[name] = object.String{Val: val, Static: true}
+		case float64:
+			fields[name] = object.Number{Val: val, Static: true}
+		case bool:
+			fields[name] = object.Boolean{Val: val, Static: true}
+		default:
+			return nil, Error{Path: path + "." + name, Msg: "field values must be a string, number, boolean or null"}
+		}
+	}
+	return fields, nil
+}
+
+// attributeValue decodes the value of an employee attribute of type typ.
+func attributeValue(typ types.Type, raw json.RawMessage) (object.Object, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return object.Null{}, nil
+	}
+	switch typ {
+	case types.T_STRING:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return object.String{Val: s, Static: true}, err
+	case types.T_NUMBER:
+		var n float64
+		err := json.Unmarshal(raw, &n)
+		return object.Number{Val: n, Static: true}, err
+	case types.T_BOOL:
+		var b bool
+		err := json.Unmarshal(raw, &b)
+		return object.Boolean{Val: b, Static: true}, err
+	case types.T_DATE:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		d, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, want yyyy-MM-dd", s)
+		}
+		return object.Date{Val: d, Static: true}, nil
+	}
+	return nil, fmt.Errorf("unsupported attribute type %q, want one of %s, %s, %s or %s",
+		typ, types.T_STRING, types.T_NUMBER, types.T_BOOL, types.T_DATE)
+}
+
+func parseDate(path, s string) (time.Time, error) {
+	d, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, Error{Path: path, Msg: fmt.Sprintf("invalid date %q, want yyyy-MM-dd", s)}
+	}
+	return d, nil
+}
+
+// parseTime parses s as a time of day on date.
+func parseTime(path string, date time.Time, s string) (time.Time, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}, Error{Path: path, Msg: fmt.Sprintf("invalid time %q, want hh:mm", s)}
+	}
+	return date.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+}
+
+// parseWeekday parses the name of a day of the week. The week starts on Sunday if
+// s is empty.
+func parseWeekday(s string) (time.Weekday, error) {
+	if s == "" {
+		return time.Sunday, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), s) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q", s)
+}
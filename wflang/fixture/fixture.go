@@ -0,0 +1,130 @@
+// Package fixture loads employee data from local JSON files so that formulas can be
+// evaluated against it. A fixture describes a single employee: their pay period
+// calendar, employee attributes, and the time records, schedule records, exceptions
+// and TOR details on each of their days. See docs/fixtures.md for the file format.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// Fixture is the data of one employee, as loaded from a fixture file.
+type Fixture struct {
+	Employee    string
+	WeekStart   time.Weekday
+	Periods     []Period // in date order.
+	Attributes  []object.Attribute
+	TimeRecords []object.TimeRecord
+	Schedules   []object.ScheduleRecord
+	Exceptions  []object.Exception
+	TORDetails  []object.TORDetailRecord
+}
+
+// Period is a pay period. Start and End are inclusive.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns true if day falls within the period.
+func (p Period) Contains(day time.Time) bool {
+	return !day.Before(p.Start) && !day.After(p.End)
+}
+
+// Error is a problem with the contents of a fixture.
+type Error struct {
+	Path string // path to the offending value, e.g. days[0].time[1].start.
+	Msg  string
+}
+
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// Load reads and parses the fixture file at path.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse parses a fixture from its JSON encoding. Unknown keys are rejected, so
+// that typos in field names are not silently ignored.
+func Parse(data []byte) (*Fixture, error) {
+	var raw fixtureJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw.build()
+}
+
+// Period returns the pay period containing day, or false if the fixture has none.
+func (f *Fixture) Period(day time.Time) (Period, bool) {
+	for _, p := range f.Periods {
+		if p.Contains(day) {
+			return p, true
+		}
+	}
+	return Period{}, false
+}
+
+// Week returns the first and last days of the week containing day.
+func (f *Fixture) Week(day time.Time) (start, end time.Time) {
+	offset := (int(day.Weekday()) - int(f.WeekStart) + 7) % 7
+	start = day.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 6)
+}
+
+// Days returns every day that has records in the fixture, in date order.
+func (f *Fixture) Days() []time.Time {
+	var days []time.Time
+	add := func(d time.Time) {
+		if !slices.ContainsFunc(days, d.Equal) {
+			days = append(days, d)
+		}
+	}
+	for _, r := range f.TimeRecords {
+		add(r.Date)
+	}
+	for _, r := range f.Schedules {
+		add(r.Date)
+	}
+	for _, r := range f.Exceptions {
+		add(r.Date)
+	}
+	for _, r := range f.TORDetails {
+		add(r.Date)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return days
+}
+
+// Attribute returns the named employee attribute effective on day, or false if
+// there is none. Names are matched case-insensitively.
+func (f *Fixture) Attribute(name string, day time.Time) (object.Attribute, bool) {
+	for _, a := range f.Attributes {
+		if strings.EqualFold(a.Name, name) && a.EffectiveOn(day) {
+			return a, true
+		}
+	}
+	return object.Attribute{}, false
+}
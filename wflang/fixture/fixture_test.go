@@ -0,0 +1,138 @@
+package fixture
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/eval"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+func date(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+func TestLoad(t *testing.T) {
+	f, err := Load("testdata/employee.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Employee != "E1001" || f.WeekStart != time.Monday || len(f.Periods) != 2 {
+		t.Errorf("employee/calendar: have %q %s %d periods", f.Employee, f.WeekStart, len(f.Periods))
+	}
+	if len(f.TimeRecords) != 3 || len(f.Schedules) != 2 || len(f.Exceptions) != 1 || len(f.TORDetails) != 1 {
+		t.Fatalf("record counts: have %d time, %d schedule, %d exception, %d TOR",
+			len(f.TimeRecords), len(f.Schedules), len(f.Exceptions), len(f.TORDetails))
+	}
+
+	if have := f.TimeRecords[0].Hours; have != 7.5 {
+		t.Errorf("hours from start and end: have %v, want 7.5", have)
+	}
+	overnight := f.TimeRecords[1]
+	if want := date(2024, 1, 11).Add(6 * time.Hour); !overnight.End.Equal(want) {
+		t.Errorf("overnight end: have %s, want %s", overnight.End, want)
+	}
+	if obj, ok := overnight.Field("department"); !ok || obj != (object.String{Val: "ICU", Static: true}) {
+		t.Errorf("custom field: have %+v, %t", obj, ok)
+	}
+	if obj, ok := overnight.Field("work_rule"); !ok || obj != (object.String{Val: "NIGHTS", Static: true}) {
+		t.Errorf("work rule: have %+v, %t", obj, ok)
+	}
+	if obj, _ := f.TimeRecords[2].Field(object.FieldStartDttm); obj != (object.Null{}) {
+		t.Errorf("missing start time: have %+v, want null", obj)
+	}
+
+	if a, ok := f.Attribute("union", date(2024, 1, 10)); !ok || a.Val != (object.String{Val: "LOCAL_40", Static: true}) {
+		t.Errorf("attribute: have %+v, %t", a, ok)
+	}
+	if have := f.Days(); len(have) != 3 || !have[2].Equal(date(2024, 1, 12)) {
+		t.Errorf("days: have %v", have)
+	}
+}
+
+func TestContext(t *testing.T) {
+	f, err := Load("testdata/employee.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		day     time.Time
+		formula string
+		want    any
+	}{
+		{date(2024, 1, 10), `sumTime(over day alias t, t.HOURS)`, 10.0},
+		{date(2024, 1, 10), `sumTime(over week alias t, t.HOURS, where t.PAY_CODE = "REG")`, 15.5},
+		{date(2024, 1, 10), `countException(over period alias e, where e.EXCEPTION_CODE = "EARLY_OUT")`, 1.0},
+		{date(2024, 1, 10), `week.start`, date(2024, 1, 8)},
+		{date(2024, 1, 16), `period.end`, date(2024, 1, 28)},
+		{date(2024, 1, 16), `sumSchedule(over period alias s, s.HOURS)`, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			ctx, err := f.Context(tt.day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prs := parser.New(lexer.New(tt.formula))
+			root, err := prs.AST()
+			if err != nil {
+				t.Fatal(err)
+			}
+			obj, err := eval.Eval(root, ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have, _ := obj.Value(); have != tt.want {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+
+	if _, err := f.Context(date(2024, 2, 1)); err == nil {
+		t.Error("expected an error for a day outside every pay period")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const calendar = `"calendar": {"periods": [{"start": "2024-01-01", "end": "2024-01-14"}]}`
+
+	tests := []struct {
+		input    string
+		wantPath string
+	}{
+		{`{"calendar": {"periods": []}}`, "calendar.periods"},
+		{`{"calendar": {"weekStart": "someday", "periods": [{"start": "2024-01-01", "end": "2024-01-14"}]}}`, "calendar.weekStart"},
+		{`{"calendar": {"periods": [{"start": "2024-01-01", "end": "2024-01-14"}, {"start": "2024-01-14", "end": "2024-01-28"}]}}`, "calendar.periods"},
+		{`{` + calendar + `, "days": [{"date": "2024-1-1"}]}`, "days[0].date"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01"}, {"date": "2024-01-01"}]}`, "days[1].date"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "time": [{"hours": 8}]}]}`, "days[0].time[0].payCode"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "time": [{"payCode": "REG"}]}]}`, "days[0].time[0].hours"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "schedule": [{"payCode": "REG", "start": "8am", "end": "16:00"}]}]}`, "days[0].schedule[0].start"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "time": [{"payCode": "REG", "hours": 1, "fields": {"X": [1]}}]}]}`, "days[0].time[0].fields.X"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "exceptions": [{"severity": "LOW"}]}]}`, "days[0].exceptions[0].code"},
+		{`{` + calendar + `, "days": [{"date": "2024-01-01", "tor": [{"hours": 8}]}]}`, "days[0].tor[0].id"},
+		{`{` + calendar + `, "attributes": [{"name": "A", "type": "timeRecord", "value": 1}]}`, "attributes[0].value"},
+		{`{` + calendar + `, "attributes": [{"name": "A", "type": "number", "value": "1"}]}`, "attributes[0].value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantPath, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			var fixErr Error
+			if !errors.As(err, &fixErr) {
+				t.Fatalf("have error %v, want a fixture.Error", err)
+			}
+			if fixErr.Path != tt.wantPath {
+				t.Errorf("path: have %q, want %q (%s)", fixErr.Path, tt.wantPath, err)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte(`{"employe": "E1"}`)); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
@@ -0,0 +1,98 @@
+// Package format pretty prints WFLang formulas. Keywords are lower cased, builtin
+// names take the casing of the builtin catalogue, and calls that do not fit on a
+// line, as well as summary functions, are laid out in leading-comma style:
+//
+//	sumTime( over day alias x
+//	       , x.HOURS
+//	       , where x.PAY_CODE = "REG" )
+//
+// Formulas with syntax errors are not formatted.
+package format
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+// Options control the layout of formatted formulas.
+type Options struct {
+	Indent    int // spaces by which wrapped and/or chains are indented.
+	LineWidth int // lines longer than this are wrapped where possible.
+}
+
+// DefaultOptions returns the Options used when none are configured.
+func DefaultOptions() Options {
+	return Options{Indent: 4, LineWidth: 100}
+}
+
+// ErrSyntax is returned when asked to format a formula with syntax errors.
+var ErrSyntax = errors.New("cannot format a formula with syntax errors")
+
+// Source formats the formula src.
+func Source(src string, opts Options) (string, error) {
+	prs := parser.New(lexer.New(src))
+	root, err := prs.AST()
+	if err != nil {
+		return "", err
+	}
+	if len(prs.Errors()) > 0 {
+		return "", ErrSyntax
+	}
+	return Statements(root.Statements, opts), nil
+}
+
+// Statements formats stmts, which must be free of parse errors, as consecutive
+// lines starting at column 0. Single blank lines between statements are kept, as
+// are comments that trail a statement on the same line.
+func Statements(stmts []ast.Statement, opts Options) string {
+	p := &printer{opts: opts}
+	for i, stmt := range stmts {
+		if i > 0 {
+			_, prevEnd := stmts[i-1].Pos()
+			start, _ := stmt.Pos()
+			switch {
+			case start.Line == prevEnd.Line && isComment(stmt):
+				p.write(" ")
+			case start.Line > prevEnd.Line+1:
+				p.write("\n\n")
+			default:
+				p.write("\n")
+			}
+		}
+		p.stmt(stmt)
+	}
+	if len(stmts) > 0 {
+		p.write("\n")
+	}
+	return p.out.String()
+}
+
+func isComment(stmt ast.Statement) bool {
+	switch stmt.(type) {
+	case ast.LineCommentStatement, ast.BlockCommentStatement:
+		return true
+	}
+	return false
+}
+
+// Node formats a single node as it would be printed on one line, regardless of its
+// length. Comments within the node are left out. It is otherwise lossless where
+// ast.Node.String is not, and is suitable for messages and hover text.
+func Node(n ast.Node) string {
+	s, _ := flat(n)
+	return s
+}
+
+// flat prints n on one line, leaving out comments. It returns true if there were
+// any.
+func flat(n ast.Node) (s string, trivia bool) {
+	p := &printer{flat: true}
+	p.node(n)
+	return p.out.String(), p.trivia
+}
+
+func spaces(n int) string { return strings.Repeat(" ", max(n, 0)) }
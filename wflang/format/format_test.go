@@ -0,0 +1,148 @@
+package format
+
+import (
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"keywords", `VAR a = TRUE AND NOT x IN SET MY_SET;`, "var a = true and not x in set MY_SET;\n"},
+		{"null", `if(x = NULL, 0, x)`, "if(x = null, 0, x)\n"},
+		{"operators", `1+2*-x`, "1 + 2 * -x\n"},
+		{"builtin casing", `TOLOWERCASE("A")`, "toLowerCase(\"A\")\n"},
+		{"list", `x in ["a","b",  "c"]`, "x in [\"a\", \"b\", \"c\"]\n"},
+		{"short call", `if(a>1,max(1,2),0)`, "if(a > 1, max(1, 2), 0)\n"},
+		{"member", `period . end`, "period.end\n"},
+		{"method", `x . isSeverity( "HIGH" )`, "x.isSeverity(\"HIGH\")\n"},
+		{"macro", `$MY_MACRO(1,"a")$`, "$MY_MACRO(1, \"a\")$\n"},
+		{"summary with one arg", `countTime(OVER day)`, "countTime(over day)\n"},
+		{
+			"summary",
+			`sumtime(OVER day ALIAS x, x.hours, WHERE x.PAY_CODE="REG")`,
+			"sumTime( over day alias x\n" +
+				"       , x.hours\n" +
+				"       , where x.PAY_CODE = \"REG\" )\n",
+		},
+		{
+			"nested summary",
+			`if(sumTime(over day alias x, x.HOURS) > 8, "OT", "REG")`,
+			"if( sumTime( over day alias x\n" +
+				"           , x.HOURS ) > 8\n" +
+				"  , \"OT\"\n" +
+				"  , \"REG\" )\n",
+		},
+		{
+			"order by",
+			`findFirstTime(over period alias t, where t.HOURS > 0, order by t.HOURS DESC).PAY_CODE`,
+			"findFirstTime( over period alias t\n" +
+				"             , where t.HOURS > 0\n" +
+				"             , order by t.HOURS desc ).PAY_CODE\n",
+		},
+		{"block fits", `(var a = 1; a + 1)`, "(var a = 1; a + 1)\n"},
+		{
+			"comments and blank lines",
+			"// total hours\nvar a = 1;   // one\n\n\n\n/* two */\na",
+			"// total hours\nvar a = 1; // one\n\n/* two */\na\n",
+		},
+		{
+			"comments in call",
+			"sumTime(over day alias x, // all time\n x.HOURS /* hours */, where x.PAY_CODE = \"REG\")",
+			"sumTime( over day alias x\n" +
+				"       , // all time\n" +
+				"         x.HOURS /* hours */\n" +
+				"       , where x.PAY_CODE = \"REG\" )\n",
+		},
+		{
+			"comment in chain",
+			"a = 1 and // first\nb = 2",
+			"a = 1\n    and // first\n    b = 2\n",
+		},
+		{
+			"comment in block",
+			"(var a = 1; // one\n// the value\na + 1)",
+			"( var a = 1; // one\n  // the value\n  a + 1 )\n",
+		},
+		{
+			"multi-line block comment",
+			"max(1, /* one\n   two */ 2)",
+			"max( 1\n   , /* one\n   two */ 2 )\n",
+		},
+		{"trailing comment", "var a = 1; // one\na", "var a = 1; // one\na\n"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := Source(tt.input, DefaultOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tt.want {
+				t.Errorf("have:\n%s\nwant:\n%s", have, tt.want)
+			}
+			again, err := Source(have, DefaultOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again != have {
+				t.Errorf("not idempotent, second pass:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestSourceOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		want  string
+	}{
+		{
+			"wrapped call",
+			`max(100, 200, 300)`,
+			Options{Indent: 4, LineWidth: 10},
+			"max( 100\n   , 200\n   , 300 )\n",
+		},
+		{
+			"wrapped logical chain",
+			`a = 1 and b = 2 or c`,
+			Options{Indent: 2, LineWidth: 10},
+			"a = 1\n  and b = 2\n  or c\n",
+		},
+		{
+			"wrapped block",
+			`(var first = 1; var second = 2; first + second)`,
+			Options{Indent: 4, LineWidth: 20},
+			"( var first = 1;\n  var second = 2;\n  first + second )\n",
+		},
+		{
+			"wrapped list",
+			`x in ["aaaa", "bbbb"]`,
+			Options{Indent: 4, LineWidth: 12},
+			"x in [ \"aaaa\"\n     , \"bbbb\" ]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := Source(tt.input, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tt.want {
+				t.Errorf("have:\n%s\nwant:\n%s", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceSyntaxError(t *testing.T) {
+	if _, err := Source(`sumTime(over day,`, DefaultOptions()); err != ErrSyntax {
+		t.Errorf("have error %v, want %v", err, ErrSyntax)
+	}
+}
@@ -0,0 +1,416 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// printer writes formatted nodes to out, tracking the column it is writing at so
+// that wrapped lines can be aligned.
+type printer struct {
+	opts   Options
+	flat   bool // if true, nothing is wrapped and comments are left out.
+	trivia bool // set if a flat printer left out any comments.
+	out    strings.Builder
+	col    int
+	indent int  // the column to continue at after a line comment.
+	broken bool // if true, a line comment was written and the next write must start a new line.
+}
+
+func (p *printer) write(s string) {
+	if p.broken {
+		if strings.Trim(s, " ") == "" {
+			// spacing before the new line is dropped.
+			return
+		}
+		p.broken = false
+		if s[0] != '\n' {
+			p.newline(p.indent)
+		}
+	}
+	p.out.WriteString(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		p.col = len(s) - i - 1
+	} else {
+		p.col += len(s)
+	}
+}
+
+// newline starts a new line, indented to col.
+func (p *printer) newline(col int) { p.write("\n" + spaces(col)) }
+
+// fits returns true if n can be printed on one line from the current column
+// within the line width, and contains no comments or summary functions, which
+// are always wrapped.
+func (p *printer) fits(n ast.Node) bool {
+	s, trivia := flat(n)
+	if trivia || strings.Contains(s, "\n") || p.col+len(s) > p.opts.LineWidth {
+		return false
+	}
+	fits := true
+	ast.Inspect(n, func(n ast.Node) bool {
+		if call, ok := n.(ast.BuiltinCall); ok && isSummary(call) && len(call.Args) > 1 {
+			fits = false
+		}
+		return fits
+	})
+	return fits
+}
+
+// flatten prints n on one line if it fits, and returns true if it did. It always
+// returns false when the printer is flat, as the caller prints n on one line anyway.
+func (p *printer) flatten(n ast.Node) bool {
+	if p.flat || !p.fits(n) {
+		return false
+	}
+	p.write(Node(n))
+	return true
+}
+
+// tok writes text for the token tk, preceded by the comments that lead tk and
+// followed by those that trail it.
+func (p *printer) tok(tk token.Token, text string) {
+	p.leading(tk)
+	p.write(text)
+	p.trailing(tk)
+}
+
+// leading writes the comments that lead tk. The token follows on a new line at
+// the same column, unless the last comment is a block comment on the same line.
+func (p *printer) leading(tk token.Token) {
+	comments := tk.Leading()
+	if len(comments) == 0 {
+		return
+	}
+	if p.flat {
+		p.trivia = true
+		return
+	}
+	col := p.col
+	for i, c := range comments {
+		if i > 0 && !continuation(c) {
+			p.newline(col)
+		}
+		p.comment(c)
+	}
+	last := comments[len(comments)-1]
+	if last.Type == token.T_COMMENT_LINE || last.StartPos.Line < tk.StartPos.Line {
+		p.newline(col)
+	} else {
+		p.write(" ")
+	}
+}
+
+// trailing writes the comments that trail tk on the same line. After a line
+// comment, the next write starts a new line at p.indent.
+func (p *printer) trailing(tk token.Token) {
+	comments := tk.Trailing()
+	if len(comments) == 0 {
+		return
+	}
+	if p.flat {
+		p.trivia = true
+		return
+	}
+	for _, c := range comments {
+		if !continuation(c) {
+			p.write(" ")
+		}
+		p.comment(c)
+		if c.Type == token.T_COMMENT_LINE {
+			p.broken = true
+		}
+	}
+}
+
+// comment writes a single comment token. The lexer splits block comments into a
+// token per line, and the lines after the first are written as they were.
+func (p *printer) comment(c token.Token) {
+	if continuation(c) {
+		p.write("\n" + c.Literal)
+		return
+	}
+	p.write(strings.TrimRight(c.Literal, " \t\r"))
+}
+
+func continuation(c token.Token) bool {
+	return c.Type == token.T_COMMENT_BLOCK && !strings.HasPrefix(c.Literal, "/*")
+}
+
+// indentTo sets the column to continue at after a line comment, and returns a
+// func that restores the previous one.
+func (p *printer) indentTo(col int) func() {
+	prev := p.indent
+	p.indent = col
+	return func() { p.indent = prev }
+}
+
+func (p *printer) stmt(stmt ast.Statement) {
+	switch v := stmt.(type) {
+	case ast.VarStatement:
+		p.tok(v.Token, "var")
+		p.write(" ")
+		p.node(v.Name)
+		p.write(" = ")
+		p.node(v.Value)
+		p.tok(v.Semicolon, ";")
+	default:
+		p.node(stmt)
+	}
+}
+
+func (p *printer) node(n ast.Node) {
+	switch v := n.(type) {
+	case ast.ExpressionStatement:
+		p.node(v.Expression)
+	case ast.VarStatement:
+		p.stmt(v)
+	case ast.LineCommentStatement:
+		p.write(strings.TrimRight(v.Literal, " \t\r"))
+	case ast.BlockCommentStatement:
+		p.write(v.Literal)
+
+	case ast.Ident:
+		p.tok(v.Token, v.Value)
+	case ast.NumberLiteral:
+		p.tok(v.Token, v.Token.Literal)
+	case ast.StringLiteral:
+		p.tok(v.Token, v.Token.Literal)
+	case ast.BooleanLiteral:
+		p.tok(v.Token, fmt.Sprint(v.Value))
+	case ast.NullLiteral:
+		p.tok(v.Token, "null")
+	case ast.DateLiteral:
+		p.tok(v.Token, v.Token.Literal)
+	case ast.TimeLiteral:
+		p.tok(v.Token, v.Token.Literal)
+	case ast.BlankExpression:
+
+	case ast.PrefixExpression:
+		prefix := strings.ToLower(v.Prefix)
+		if prefix == "not" {
+			prefix += " "
+		}
+		p.tok(v.Token, prefix)
+		p.node(v.Right)
+	case ast.InfixExpression:
+		p.infix(v)
+	case ast.MemberExpression:
+		if !v.Call {
+			p.node(v.Object)
+			p.tok(v.Token, ".")
+			p.node(v.Member)
+			break
+		}
+		open := func() {
+			p.node(v.Object)
+			p.tok(v.Token, ".")
+			p.node(v.Member)
+			p.tok(v.LPar, "(")
+		}
+		p.list(v, open, func() { p.tok(v.RPar, ")") }, v.Args)
+	case ast.ParenExpression:
+		p.paren(v)
+	case ast.BlockExpression:
+		p.block(v)
+
+	case ast.BuiltinCall:
+		name := v.Name
+		if fn, ok := object.Builtin(v.Name); ok {
+			name = fn.Name
+		}
+		open := func() {
+			p.tok(v.Token, name)
+			p.tok(v.LPar, "(")
+		}
+		p.list(v, open, func() { p.tok(v.Last, ")") }, v.Args)
+	case ast.MacroExpression:
+		open := func() {
+			p.write("$")
+			p.tok(v.Name.Token, v.Name.Value)
+			p.tok(v.LPar, "(")
+		}
+		close := func() {
+			p.tok(v.RPar, ")")
+			p.tok(v.RDollar, "$")
+		}
+		p.list(v, open, close, v.Args)
+	case ast.OverExpression:
+		p.tok(v.Token, "over")
+		p.write(" ")
+		p.node(v.Context)
+		if v.HasAlias {
+			p.write(" ")
+			p.node(v.Alias)
+		}
+	case ast.AliasExpression:
+		p.tok(v.Token, "alias")
+		p.write(" ")
+		p.node(v.Alias)
+	case ast.WhereExpression:
+		p.tok(v.Token, "where")
+		p.write(" ")
+		p.node(v.Condition)
+	case ast.OrderByExpression:
+		p.tok(v.Token, "order")
+		p.write(" by ")
+		p.node(v.Expression)
+		if v.Asc != nil {
+			p.write(" ")
+			p.tok(*v.Asc, strings.ToLower(v.Ascending()))
+		}
+	case ast.InExpression:
+		p.node(v.Left)
+		p.write(" ")
+		p.tok(v.Token, "in")
+		p.write(" ")
+		p.node(v.List)
+	case ast.SetExpression:
+		p.tok(v.Token, "set")
+		p.write(" ")
+		p.node(v.Name)
+	case ast.ListLiteral:
+		items := make([]ast.Expression, len(v.Strings))
+		for i, s := range v.Strings {
+			items[i] = s
+		}
+		p.list(v, func() { p.tok(v.Token, "[") }, func() { p.tok(v.RBracket, "]") }, items)
+
+	default:
+		// anything else, e.g. a ParseErr, is printed as it was parsed.
+		p.write(n.String())
+	}
+}
+
+// infix prints an infix expression on one line if it fits. Otherwise, a chain of
+// and/or operators is wrapped before each operator, indented from the start of the
+// chain.
+func (p *printer) infix(v ast.InfixExpression) {
+	op := strings.ToLower(v.Infix)
+	if p.flatten(v) {
+		return
+	}
+	if p.flat || !isLogical(op) {
+		p.node(v.Left)
+		p.write(" ")
+		p.tok(v.Token, op)
+		p.write(" ")
+		p.node(v.Right)
+		return
+	}
+
+	chain := []ast.InfixExpression{v}
+	left := v.Left
+	for {
+		inner, ok := left.(ast.InfixExpression)
+		if !ok || strings.ToLower(inner.Infix) != op {
+			break
+		}
+		chain = append(chain, inner)
+		left = inner.Left
+	}
+	start := p.col
+	defer p.indentTo(start + p.opts.Indent)()
+	p.node(left)
+	for i := len(chain) - 1; i >= 0; i-- {
+		p.newline(start + p.opts.Indent)
+		p.tok(chain[i].Token, op)
+		p.write(" ")
+		p.node(chain[i].Right)
+	}
+}
+
+func isLogical(op string) bool {
+	switch op {
+	case "and", "or", "&&", "||":
+		return true
+	}
+	return false
+}
+
+// list prints the items of a call or list between open and close, on one line if
+// they fit, or else in leading-comma style with the commas aligned under the last
+// character written by open.
+func (p *printer) list(n ast.Node, open, close func(), items []ast.Expression) {
+	if p.flatten(n) {
+		return
+	}
+	open()
+	if p.flat || len(items) < 2 {
+		for i, item := range items {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.node(item)
+		}
+		close()
+		return
+	}
+
+	p.write(" ")
+	col := p.col - 2
+	defer p.indentTo(col + 2)()
+	for i, item := range items {
+		if i > 0 {
+			p.newline(col)
+			p.write(", ")
+		}
+		p.node(item)
+	}
+	p.write(" ")
+	close()
+}
+
+// paren prints a parenthesised expression. If it declares vars, each is printed on
+// its own line, aligned after the opening parenthesis.
+func (p *printer) paren(v ast.ParenExpression) {
+	if p.flatten(v) {
+		return
+	}
+	block, ok := v.Inner.(ast.BlockExpression)
+	if p.flat || !ok || len(block.Vars) == 0 {
+		p.tok(v.Token, "(")
+		p.node(v.Inner)
+		p.tok(v.RParen, ")")
+		return
+	}
+	p.tok(v.Token, "(")
+	p.write(" ")
+	p.block(block)
+	p.write(" ")
+	p.tok(v.RParen, ")")
+}
+
+// block prints the vars and value of a block, each on its own line aligned at the
+// current column.
+func (p *printer) block(v ast.BlockExpression) {
+	col := p.col
+	defer p.indentTo(col)()
+	for _, vs := range v.Vars {
+		p.stmt(vs)
+		if p.flat {
+			p.write(" ")
+		} else {
+			p.newline(col)
+		}
+	}
+	p.node(v.Value)
+}
+
+// isSummary returns true if call is a summary function, i.e. one whose first
+// argument is an over clause.
+func isSummary(call ast.BuiltinCall) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	arg := call.Args[0]
+	if block, ok := arg.(ast.BlockExpression); ok && len(block.Vars) == 0 {
+		arg = block.Value
+	}
+	_, ok := arg.(ast.OverExpression)
+	return ok
+}
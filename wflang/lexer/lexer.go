@@ -16,7 +16,7 @@ import (
 // New creates a new lexer and advances it into the first byte within the input
 // string.
 func New(input string) *Lexer {
-	l := &Lexer{input: input, lines: []uint{0}}
+	l := &Lexer{input: input, lines: []uint{0}, lineStarts: []uint{0}}
 	l.advance()
 	return l
 }
@@ -32,15 +32,16 @@ func Keyword(s string) (token.Type, bool) {
 
 // Lexer is the font of all semantic tokens. Here be words.
 type Lexer struct {
-	input     string     // Holds the entire text context of the Lexer
-	pos       uint       // Current position in input
-	next      uint       // Next reading position (char after pos)
-	ch        byte       // The current character
-	line      uint       // The current line
-	lPos      uint       // The position within the current line
-	lines     []uint     // Slice holding the lengths of all lines. Updated whenever lexer advances
-	multiline bool       // True if lexer is currently processing a multiline structure e.g. block comments
-	multiType token.Type // The token type currently being processed if multiline is true
+	input      string     // Holds the entire text context of the Lexer
+	pos        uint       // Current position in input
+	next       uint       // Next reading position (char after pos)
+	ch         byte       // The current character
+	line       uint       // The current line
+	lPos       uint       // The position within the current line
+	lines      []uint     // Slice holding the lengths of all lines. Updated whenever lexer advances
+	lineStarts []uint     // Slice holding the byte offset at which each line starts
+	multiline  bool       // True if lexer is currently processing a multiline structure e.g. block comments
+	multiType  token.Type // The token type currently being processed if multiline is true
 }
 
 func (l *Lexer) logDebug() {
@@ -75,6 +76,7 @@ func (l *Lexer) NextToken() token.Token {
 	case eof:
 		tok = newToken(l, token.T_EOF, eof, l.here())
 		tok.Literal = ""
+		tok.Offset = uint(len(l.input))
 
 	// this is always a one-shot as = is both assignment and equality. There is no double =.
 	case '=':
@@ -254,6 +256,7 @@ func newToken[T token.Literal](l *Lexer, tType token.Type, lit T, start token.Po
 		Type:     tType,
 		Literal:  string(lit),
 		Len:      tLen,
+		Offset:   l.offset(start),
 		StartPos: start,
 		EndPos: token.Pos{
 			Line: l.line,
@@ -295,9 +298,19 @@ func (l *Lexer) advance() {
 	if l.ch == '\n' {
 		l.line++
 		l.lines = append(l.lines, 0)
+		l.lineStarts = append(l.lineStarts, l.pos+1)
 	}
 }
 
+// offset returns the byte offset within the input of a position already passed
+// by the Lexer.
+func (l *Lexer) offset(pos token.Pos) uint {
+	if int(pos.Line) >= len(l.lineStarts) {
+		return uint(len(l.input))
+	}
+	return min(l.lineStarts[pos.Line]+pos.Col, uint(len(l.input)))
+}
+
 // peek returns the character in the next position without advancing the Lexer.
 func (l *Lexer) peek() byte {
 	if l.next >= uint(len(l.input)) {
@@ -431,6 +444,7 @@ func (l *Lexer) processMultiline() token.Token {
 	if l.ch == eof {
 		tk := newToken(l, token.T_EOF, eof, l.here())
 		tk.Literal = ""
+		tk.Offset = uint(len(l.input))
 		l.advance()
 		return tk
 	}
@@ -155,59 +155,70 @@ x * 42
 	l := New(input)
 
 	tests := []struct {
-		n     int
-		start token.Pos
-		end   token.Pos
+		n      int
+		start  token.Pos
+		end    token.Pos
+		offset uint
 	}{
 		{
 			0,
 			token.Pos{Line: 0, Col: 0},
 			token.Pos{Line: 0, Col: 2},
+			0,
 		}, // var
 		{
 			1,
 			token.Pos{Line: 0, Col: 4},
 			token.Pos{Line: 0, Col: 4},
+			4,
 		}, // x
 		{
 			2,
 			token.Pos{Line: 0, Col: 6},
 			token.Pos{Line: 0, Col: 6},
+			6,
 		}, // =
 		{
 			3,
 			token.Pos{Line: 0, Col: 8},
 			token.Pos{Line: 0, Col: 8},
+			8,
 		}, // 1
 		{
 			4,
 			token.Pos{Line: 0, Col: 9},
 			token.Pos{Line: 0, Col: 9},
+			9,
 		}, // ;
 		{
 			5,
 			token.Pos{Line: 1, Col: 0},
 			token.Pos{Line: 1, Col: 0},
+			11,
 		}, // x
 		{
 			6,
 			token.Pos{Line: 1, Col: 2},
 			token.Pos{Line: 1, Col: 2},
+			13,
 		}, // *
 		{
 			7,
 			token.Pos{Line: 1, Col: 4},
 			token.Pos{Line: 1, Col: 5},
+			15,
 		}, // 42
 		{
 			8,
 			token.Pos{Line: 2, Col: 0},
 			token.Pos{Line: 2, Col: 36},
+			18,
 		}, // "so long and thanks for all the fish"
 		{
 			9,
 			token.Pos{Line: 2, Col: 36},
 			token.Pos{Line: 2, Col: 36},
+			55,
 		}, // EOF
 	}
 
@@ -221,6 +232,9 @@ x * 42
 			if tk.EndPos != tt.end {
 				t.Fatalf("end = %v, want %v", tk.EndPos, tt.end)
 			}
+			if tk.Offset != tt.offset {
+				t.Fatalf("offset = %d, want %d", tk.Offset, tt.offset)
+			}
 		})
 	}
 
@@ -0,0 +1,220 @@
+// Package lint reports code that is valid WFLang but likely to be a mistake, or
+// that could be written more clearly. Each check is a Rule, identified by a stable
+// ID, and can be configured individually with a Config.
+//
+// Formulas with syntax errors are not linted, as their AST is incomplete.
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// Severity is how serious a Diagnostic is. The values match the diagnostic
+// severities of the language server protocol, with the addition of SeverityOff.
+type Severity int
+
+const (
+	SeverityError Severity = iota + 1
+	SeverityWarning
+	SeverityInfo
+	SeverityHint
+	SeverityOff // disables a rule.
+)
+
+func severities() map[string]Severity {
+	return map[string]Severity{
+		"error":   SeverityError,
+		"warning": SeverityWarning,
+		"info":    SeverityInfo,
+		"hint":    SeverityHint,
+		"off":     SeverityOff,
+	}
+}
+
+func (s Severity) String() string {
+	for name, sev := range severities() {
+		if sev == s {
+			return name
+		}
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity returns the Severity named s, one of error, warning, info, hint
+// or off.
+func ParseSeverity(s string) (Severity, error) {
+	if sev, ok := severities()[strings.ToLower(s)]; ok {
+		return sev, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q, want one of error, warning, info, hint or off", s)
+}
+
+func (s Severity) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
+// Diagnostic is a problem reported by a rule.
+type Diagnostic struct {
+	Rule     string // the ID of the rule that reported it.
+	Severity Severity
+	Msg      string
+	Start    token.Pos
+	End      token.Pos
+}
+
+func (d Diagnostic) Error() string               { return d.Msg }
+func (d Diagnostic) Code() string                { return d.Rule }
+func (d Diagnostic) Pos() (start, end token.Pos) { return d.Start, d.End }
+
+// Rule is a single check run over a formula.
+type Rule interface {
+	ID() string         // stable identifier used in configuration and diagnostics.
+	Doc() string        // a sentence describing what the rule reports.
+	Severity() Severity // the severity of its diagnostics, unless configured.
+	Check(pass *Pass)
+}
+
+// Configurable is implemented by rules that take options.
+type Configurable interface {
+	Rule
+	ValidateOptions(opts map[string]string) error
+}
+
+// Pass is a run of a Rule over a formula, holding everything the rule may
+// inspect.
+type Pass struct {
+	AST     *ast.AST
+	Info    *resolve.Info // the names of AST, resolved against the record schemas of the parser.
+	Tokens  []token.Token // the token stream of the formula, including comments.
+	Options map[string]string
+
+	typeOf   func(ast.Node) (object.Object, bool)
+	rule     string
+	severity Severity
+	diags    []Diagnostic
+}
+
+// TypeOf returns the object resolved for n by the parser, if any.
+func (p *Pass) TypeOf(n ast.Node) (object.Object, bool) { return p.typeOf(n) }
+
+// Option returns the option name of the rule, or def if it is not set.
+func (p *Pass) Option(name, def string) string {
+	if v, ok := p.Options[name]; ok {
+		return v
+	}
+	return def
+}
+
+// Report records a diagnostic spanning start to end.
+func (p *Pass) Report(start, end token.Pos, format string, a ...any) {
+	p.diags = append(p.diags, Diagnostic{
+		Rule:     p.rule,
+		Severity: p.severity,
+		Msg:      fmt.Sprintf(format, a...),
+		Start:    start,
+		End:      end,
+	})
+}
+
+// ReportNode records a diagnostic spanning the node n.
+func (p *Pass) ReportNode(n ast.Node, format string, a ...any) {
+	start, end := n.Pos()
+	p.Report(start, end, format, a...)
+}
+
+// Config configures rules by ID. Rules that are not configured run with their
+// default severity and options.
+type Config map[string]RuleConfig
+
+// RuleConfig configures a single rule.
+type RuleConfig struct {
+	Severity Severity          `json:"severity,omitempty"` // zero keeps the default of the rule.
+	Options  map[string]string `json:"options,omitempty"`
+}
+
+// Validate checks that every rule configured exists among rules, or the built-in
+// Rules if there are none, and that their options are valid.
+func (c Config) Validate(rules ...Rule) error {
+	if len(rules) == 0 {
+		rules = Rules()
+	}
+	for id, rc := range c {
+		i := slices.IndexFunc(rules, func(r Rule) bool { return r.ID() == id })
+		if i < 0 {
+			return fmt.Errorf("unknown lint rule %q", id)
+		}
+		if rc.Severity < 0 || rc.Severity > SeverityOff {
+			return fmt.Errorf("lint rule %s: invalid severity %d", id, rc.Severity)
+		}
+		conf, ok := rules[i].(Configurable)
+		if !ok && len(rc.Options) > 0 {
+			return fmt.Errorf("lint rule %s takes no options", id)
+		}
+		if ok {
+			if err := conf.ValidateOptions(rc.Options); err != nil {
+				return fmt.Errorf("lint rule %s: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Lint runs rules, or the built-in Rules if there are none, over the formula
+// parsed by prs. Rules are configured by cfg, which may be nil. The diagnostics
+// are sorted by position.
+func Lint(prs *parser.Parser, cfg Config, rules ...Rule) []Diagnostic {
+	root, err := prs.AST()
+	if err != nil || len(prs.Errors()) > 0 {
+		return nil
+	}
+	if len(rules) == 0 {
+		rules = Rules()
+	}
+
+	var diags []Diagnostic
+	for _, rule := range rules {
+		rc := cfg[rule.ID()]
+		pass := &Pass{
+			AST:      root,
+			Info:     prs.Resolved(),
+			Tokens:   prs.Tokens(),
+			Options:  rc.Options,
+			typeOf:   prs.TypeOf,
+			rule:     rule.ID(),
+			severity: rule.Severity(),
+		}
+		if rc.Severity != 0 {
+			pass.severity = rc.Severity
+		}
+		if pass.severity == SeverityOff {
+			continue
+		}
+		rule.Check(pass)
+		diags = append(diags, pass.diags...)
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].Start.LT(diags[j].Start) })
+	return diags
+}
+
+// Source parses and lints the formula src.
+func Source(src string, cfg Config, rules ...Rule) []Diagnostic {
+	return Lint(parser.New(lexer.New(src)), cfg, rules...)
+}
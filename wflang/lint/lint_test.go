@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // "rule line:col" of each diagnostic.
+	}{
+		{"clean", "var a = 1;\nif(a > 1 && a < 5, a, 0)", nil},
+		{"unused var", "var a = 1;\nvar b = 2;\na", []string{"unused-var 1:4"}},
+		{"unused block var", "(var a = 1; 2)", []string{"unused-var 0:5"}},
+		{"var used by a later var", "var a = 1;\nvar b = a;\nb", nil},
+		{"var names are case insensitive", "var Total = 1;\nTOTAL", nil},
+		{"alias and field are not vars", "var x = 1;\nsumTime(over day alias x, x.x)", []string{"unused-var 0:4"}},
+		{
+			"shadowed var",
+			"var a = 1;\na + (var a = 2; a)",
+			[]string{"shadowed-var 1:9"},
+		},
+		{"word operators", "var a = true;\na and not a", []string{"operator-style 1:2", "operator-style 1:6"}},
+		{"redundant parens around a value", "var a = 1;\n(a) + 1", []string{"redundant-parens 1:0"}},
+		{"redundant parens around the statement", "var a = 1;\n(a + 1)", []string{"redundant-parens 1:0"}},
+		{"redundant parens around an argument", "var a = 1;\nmax((a + 1), 2)", []string{"redundant-parens 1:4"}},
+		{"double parens", "var a = 1;\n((a + 1)) * 2", []string{"redundant-parens 1:1"}},
+		{"needed parens", "var a = 1;\n(a + 1) * 2", nil},
+		{"parens declaring vars", "(var a = 1; a)", nil},
+		{"null comparison", "var a = 1;\nif(a = null, 0, a)", []string{"null-comparison 1:3"}},
+		{"redundant if", "var a = 1;\nif(a > 1, true, false)", []string{"redundant-if 1:0"}},
+		{"negated redundant if", "var a = 1;\nif(a > 1, FALSE, TRUE)", []string{"redundant-if 1:0"}},
+		{"if of another type", "var a = 1;\nif(a, true, false)", nil},
+		{"syntax errors are not linted", "var a = 1;\nmax(", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var have []string
+			for _, d := range Source(tt.input, nil) {
+				have = append(have, fmt.Sprintf("%s %d:%d", d.Rule, d.Start.Line, d.Start.Col))
+			}
+			if strings.Join(have, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	input := "var a = 1;\nvar b = true;\nb and true"
+
+	var cfg Config
+	data := `{"unused-var": {"severity": "error"}, "operator-style": {"options": {"style": "words"}}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	diags := Source(input, cfg)
+	if len(diags) != 1 || diags[0].Rule != "unused-var" || diags[0].Severity != SeverityError {
+		t.Errorf("have %+v, want a single unused-var error", diags)
+	}
+
+	cfg = Config{"unused-var": {Severity: SeverityOff}}
+	if diags := Source(input, cfg); len(diags) != 1 || diags[0].Rule != "operator-style" {
+		t.Errorf("have %+v, want a single operator-style diagnostic", diags)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []Config{
+		{"no-such-rule": {}},
+		{"unused-var": {Options: map[string]string{"a": "b"}}},
+		{"operator-style": {Options: map[string]string{"style": "emoji"}}},
+		{"operator-style": {Severity: 9}},
+	}
+	for _, cfg := range tests {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", cfg)
+		}
+	}
+
+	var sev Severity
+	if err := json.Unmarshal([]byte(`"loud"`), &sev); err == nil {
+		t.Error("expected an error unmarshalling an unknown severity")
+	}
+}
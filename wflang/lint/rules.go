@@ -0,0 +1,225 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// Rules returns the built-in rules, in the order they are run.
+func Rules() []Rule {
+	return []Rule{
+		unusedVar{},
+		shadowedVar{},
+		operatorStyle{},
+		redundantParens{},
+		nullComparison{},
+		redundantIf{},
+	}
+}
+
+type unusedVar struct{}
+
+func (unusedVar) ID() string         { return "unused-var" }
+func (unusedVar) Doc() string        { return "Reports vars that are declared but never used." }
+func (unusedVar) Severity() Severity { return SeverityWarning }
+
+func (unusedVar) Check(pass *Pass) {
+	for _, d := range pass.Info.Decls {
+		if d.Kind == resolve.KindVar && len(pass.Info.RefsTo(d)) == 0 {
+			pass.ReportNode(d.Name, "var %s is declared but never used", d.Name.Value)
+		}
+	}
+}
+
+type shadowedVar struct{}
+
+func (shadowedVar) ID() string { return "shadowed-var" }
+func (shadowedVar) Doc() string {
+	return "Reports vars declared in a block with the same name as a var of an enclosing scope."
+}
+func (shadowedVar) Severity() Severity { return SeverityWarning }
+
+func (shadowedVar) Check(pass *Pass) {
+	for _, d := range pass.Info.Decls {
+		if outer, ok := shadows(d); ok {
+			pass.ReportNode(d.Name, "var %s shadows the var declared on line %d", d.Name.Value, outer.Stmt.StartPos.Line+1)
+		}
+	}
+}
+
+// shadows returns the var of an enclosing scope that the var d hides. Aliases,
+// which are scoped to their call, are neither reported nor hidden.
+func shadows(d *resolve.Decl) (*resolve.Decl, bool) {
+	if d.Kind != resolve.KindVar {
+		return nil, false
+	}
+	for s := d.Scope.Parent; s != nil; s = s.Parent {
+		if outer, ok := s.Local(d.Name.Value, d.Name.StartPos); ok && outer.Kind == resolve.KindVar {
+			return outer, true
+		}
+	}
+	return nil, false
+}
+
+// operatorStyle takes the option "style", which is either "symbols" (the default)
+// or "words".
+type operatorStyle struct{}
+
+func (operatorStyle) ID() string { return "operator-style" }
+func (operatorStyle) Doc() string {
+	return "Reports logical operators written as words (and, or, not) rather than symbols (&&, ||, !), or the other way round."
+}
+func (operatorStyle) Severity() Severity { return SeverityInfo }
+
+func (operatorStyle) ValidateOptions(opts map[string]string) error {
+	for name, val := range opts {
+		if name != "style" {
+			return fmt.Errorf("unknown option %q", name)
+		}
+		if val != "symbols" && val != "words" {
+			return fmt.Errorf("style: have %q, want symbols or words", val)
+		}
+	}
+	return nil
+}
+
+func (operatorStyle) Check(pass *Pass) {
+	words := pass.Option("style", "symbols") == "words"
+	for _, tok := range pass.Tokens {
+		op, ok := logicalOperators()[tok.Type]
+		if !ok {
+			continue
+		}
+		isWord := strings.ToLower(tok.Literal) == op.word
+		switch {
+		case words && !isWord:
+			pass.Report(tok.StartPos, tok.EndPos, "use %q instead of %q", op.word, tok.Literal)
+		case !words && isWord:
+			pass.Report(tok.StartPos, tok.EndPos, "use %q instead of %q", op.symbol, tok.Literal)
+		}
+	}
+}
+
+func logicalOperators() map[token.Type]struct{ word, symbol string } {
+	return map[token.Type]struct{ word, symbol string }{
+		token.T_AND:  {"and", "&&"},
+		token.T_OR:   {"or", "||"},
+		token.T_BANG: {"not", "!"},
+	}
+}
+
+type redundantParens struct{}
+
+func (redundantParens) ID() string { return "redundant-parens" }
+func (redundantParens) Doc() string {
+	return "Reports parentheses around a single value, or around a whole expression, that do not declare vars."
+}
+func (redundantParens) Severity() Severity { return SeverityInfo }
+
+func (redundantParens) Check(pass *Pass) {
+	reported := map[token.Pos]bool{}
+	report := func(n ast.Expression) {
+		paren, ok := n.(ast.ParenExpression)
+		if !ok || declaresVars(paren) || reported[paren.StartPos] {
+			return
+		}
+		reported[paren.StartPos] = true
+		pass.ReportNode(paren, "redundant parentheses")
+	}
+	ast.Inspect(pass.AST, func(n ast.Node) bool {
+		switch v := n.(type) {
+		// parentheses that make up a whole expression.
+		case ast.ExpressionStatement:
+			report(v.Expression)
+		case ast.VarStatement:
+			report(v.Value)
+		case ast.BlockExpression:
+			report(v.Value)
+		case ast.WhereExpression:
+			report(v.Condition)
+
+		case ast.ParenExpression:
+			if isAtom(ast.Unblock(v.Inner)) {
+				report(v)
+			}
+		}
+		return true
+	})
+}
+
+// isAtom returns true if n never needs parentheses.
+func isAtom(n ast.Node) bool {
+	switch n.(type) {
+	case ast.Ident, ast.NumberLiteral, ast.StringLiteral, ast.BooleanLiteral, ast.NullLiteral,
+		ast.DateLiteral, ast.TimeLiteral, ast.BuiltinCall, ast.MacroExpression, ast.MemberExpression:
+		return true
+	}
+	return false
+}
+
+func declaresVars(paren ast.ParenExpression) bool {
+	block, ok := paren.Inner.(ast.BlockExpression)
+	return ok && len(block.Vars) > 0
+}
+
+type nullComparison struct{}
+
+func (nullComparison) ID() string { return "null-comparison" }
+func (nullComparison) Doc() string {
+	return "Reports comparisons with null using = or !=, which do not match fields that are blank rather than null."
+}
+func (nullComparison) Severity() Severity { return SeverityWarning }
+
+func (nullComparison) Check(pass *Pass) {
+	ast.Inspect(pass.AST, func(n ast.Node) bool {
+		infix, ok := n.(ast.InfixExpression)
+		if !ok || infix.Token.Type != token.T_EQ && infix.Token.Type != token.T_NEQ {
+			return true
+		}
+		if isNull(infix.Left) || isNull(infix.Right) {
+			pass.ReportNode(infix, "comparison with null using %q", infix.Token.Literal)
+		}
+		return true
+	})
+}
+
+func isNull(n ast.Expression) bool {
+	_, ok := n.(ast.NullLiteral)
+	return ok
+}
+
+type redundantIf struct{}
+
+func (redundantIf) ID() string { return "redundant-if" }
+func (redundantIf) Doc() string {
+	return "Reports if(cond, true, false) and if(cond, false, true), which are cond and not cond."
+}
+func (redundantIf) Severity() Severity { return SeverityWarning }
+
+func (redundantIf) Check(pass *Pass) {
+	ast.Inspect(pass.AST, func(n ast.Node) bool {
+		call, ok := n.(ast.BuiltinCall)
+		if !ok || call.Name != "if" || len(call.Args) != 3 {
+			return true
+		}
+		then, ok1 := ast.Unblock(call.Args[1]).(ast.BooleanLiteral)
+		els, ok2 := ast.Unblock(call.Args[2]).(ast.BooleanLiteral)
+		if !ok1 || !ok2 || then.Value == els.Value {
+			return true
+		}
+		if obj, ok := pass.TypeOf(ast.Unblock(call.Args[0])); ok && obj.Type() != types.T_BOOL && obj.Type() != types.T_ANY {
+			return true
+		}
+		if then.Value {
+			pass.ReportNode(call, "redundant if, use the condition itself")
+		} else {
+			pass.ReportNode(call, "redundant if, use the negated condition")
+		}
+		return true
+	})
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// Codes of the diagnostics reported about suppression comments themselves.
+const (
+	CodeUnusedSuppression  = "unused-suppression"
+	CodeInvalidSuppression = "invalid-suppression"
+)
+
+// Suppression directives, which follow the comment marker.
+const (
+	directiveIgnore     = "wflang:ignore"
+	directiveIgnoreFile = "wflang:ignore-file"
+)
+
+// suppression is a comment that hides diagnostics with a given code.
+type suppression struct {
+	Code    string
+	Reason  string
+	File    bool // applies to the whole file.
+	Line    uint // the line it applies to, unless File.
+	Comment token.Token
+	used    bool
+}
+
+// Suppressor decides which diagnostics of a formula are suppressed by comments,
+// and tracks which of them are used. A comment
+//
+//	// wflang:ignore <code> [reason]
+//
+// on the line before a diagnostic or at the end of its line suppresses it, and
+//
+//	/* wflang:ignore-file <code> [reason] */
+//
+// before any code suppresses diagnostics with the code throughout the file.
+type Suppressor struct {
+	suppressions []*suppression
+	invalid      []Diagnostic
+}
+
+// NewSuppressor reads the suppression comments among tokens, which must be the
+// token stream of a formula including comments, in order.
+func NewSuppressor(tokens []token.Token) *Suppressor {
+	s := &Suppressor{}
+	code := false // whether code has been seen.
+	codeLine := -1
+	for _, tok := range tokens {
+		if !tok.IsComment() {
+			code = true
+			codeLine = int(tok.EndPos.Line)
+			continue
+		}
+		directive, args, ok := parseDirective(tok.Literal)
+		if !ok {
+			continue
+		}
+		if len(args) == 0 || args[0] == "" {
+			s.invalidf(tok, "%s needs the code of the diagnostic to suppress", directive)
+			continue
+		}
+		sup := &suppression{Code: args[0], Reason: strings.Join(args[1:], " "), Comment: tok}
+		switch {
+		case !KnownCode(sup.Code):
+			s.invalidf(tok, "%s: unknown code %q", directive, sup.Code)
+			continue
+		case directive == directiveIgnoreFile && code:
+			s.invalidf(tok, "%s must come before any code", directive)
+			continue
+		case directive == directiveIgnoreFile:
+			sup.File = true
+		case int(tok.StartPos.Line) == codeLine:
+			sup.Line = tok.StartPos.Line // at the end of a line of code.
+		default:
+			sup.Line = tok.StartPos.Line + 1
+		}
+		s.suppressions = append(s.suppressions, sup)
+	}
+	return s
+}
+
+// parseDirective returns the directive of a suppression comment and its
+// arguments, or false if the comment is not one.
+func parseDirective(comment string) (directive string, args []string, ok bool) {
+	text := strings.TrimSpace(comment)
+	text = strings.TrimPrefix(text, "//")
+	text = strings.TrimPrefix(text, "/*")
+	text = strings.TrimSuffix(text, "*/")
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] != directiveIgnore && fields[0] != directiveIgnoreFile {
+		return "", nil, false
+	}
+	return fields[0], fields[1:], true
+}
+
+func (s *Suppressor) invalidf(tok token.Token, format string, a ...any) {
+	s.invalid = append(s.invalid, Diagnostic{
+		Rule:     CodeInvalidSuppression,
+		Severity: SeverityWarning,
+		Msg:      fmt.Sprintf(format, a...),
+		Start:    tok.StartPos,
+		End:      tok.EndPos,
+	})
+}
+
+// Suppressed returns true if a diagnostic with code starting at start is
+// suppressed, and marks the suppressions that apply as used.
+func (s *Suppressor) Suppressed(code string, start token.Pos) bool {
+	suppressed := false
+	for _, sup := range s.suppressions {
+		if sup.Code == code && (sup.File || sup.Line == start.Line) {
+			sup.used = true
+			suppressed = true
+		}
+	}
+	return suppressed
+}
+
+// Filter returns the diagnostics that are not suppressed.
+func (s *Suppressor) Filter(diags []Diagnostic) []Diagnostic {
+	return slices.DeleteFunc(slices.Clone(diags), func(d Diagnostic) bool { return s.Suppressed(d.Rule, d.Start) })
+}
+
+// Diagnostics returns the problems with the suppression comments: those that are
+// invalid, and those not used by any diagnostic. Suppressions of a code are only
+// reported as unused if checked returns true for it, as diagnostics with that code
+// were looked for. It must be called after Suppressed.
+func (s *Suppressor) Diagnostics(checked func(code string) bool) []Diagnostic {
+	diags := slices.Clone(s.invalid)
+	for _, sup := range s.suppressions {
+		if sup.used || !checked(sup.Code) {
+			continue
+		}
+		diags = append(diags, Diagnostic{
+			Rule:     CodeUnusedSuppression,
+			Severity: SeverityWarning,
+			Msg:      fmt.Sprintf("unused suppression of %s", sup.Code),
+			Start:    sup.Comment.StartPos,
+			End:      sup.Comment.EndPos,
+		})
+	}
+	return diags
+}
+
+// KnownCode returns true if code identifies a kind of diagnostic: one reported by
+// the parser or the resolver, or one of the built-in Rules.
+func KnownCode(code string) bool {
+	return slices.Contains(parser.Codes(), code) || slices.Contains(resolve.Codes(), code) || IsRule(code)
+}
+
+// IsRule returns true if id is the ID of one of the built-in Rules.
+func IsRule(id string) bool {
+	return slices.ContainsFunc(Rules(), func(r Rule) bool { return r.ID() == id })
+}
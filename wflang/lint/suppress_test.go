@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+func TestSuppressor(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // "rule line:col" of each diagnostic left.
+	}{
+		{"none", "var a = 1;\n2", []string{"unused-var 0:4"}},
+		{"same line", "var a = 1; // wflang:ignore unused-var kept for later\n2", nil},
+		{"preceding line", "// wflang:ignore unused-var\nvar a = 1;\n2", nil},
+		{"other line", "// wflang:ignore unused-var\n\nvar a = 1;\n2", []string{"unused-var 2:4", "unused-suppression 0:0"}},
+		{"other code", "var a = 1; // wflang:ignore redundant-parens\n2", []string{"unused-var 0:4", "unused-suppression 0:11"}},
+		{"file", "/* wflang:ignore-file unused-var */\nvar a = 1;\nvar b = 2;\n3", nil},
+		{"file after code", "var a = 1;\n/* wflang:ignore-file unused-var */\n2", []string{"unused-var 0:4", "invalid-suppression 1:0"}},
+		{"unknown code", "var a = 1; // wflang:ignore no-such-rule\n2", []string{"unused-var 0:4", "invalid-suppression 0:11"}},
+		{"missing code", "var a = 1; // wflang:ignore\n2", []string{"unused-var 0:4", "invalid-suppression 0:11"}},
+		{"ordinary comment", "var a = 1; // ignore this\n2", []string{"unused-var 0:4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs := parser.New(lexer.New(tt.input))
+			sup := NewSuppressor(prs.Tokens())
+			diags := append(sup.Filter(Lint(prs, nil)), sup.Diagnostics(IsRule)...)
+			var have []string
+			for _, d := range diags {
+				have = append(have, fmt.Sprintf("%s %d:%d", d.Rule, d.Start.Line, d.Start.Col))
+			}
+			if strings.Join(have, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("have %v, want %v", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressorChecked(t *testing.T) {
+	prs := parser.New(lexer.New("// wflang:ignore type-mismatch\n1"))
+	sup := NewSuppressor(prs.Tokens())
+	if diags := sup.Diagnostics(IsRule); len(diags) != 0 {
+		t.Errorf("have %+v, want no diagnostics for a code that was not checked", diags)
+	}
+	if diags := sup.Diagnostics(KnownCode); len(diags) != 1 || diags[0].Rule != CodeUnusedSuppression {
+		t.Errorf("have %+v, want an unused suppression", diags)
+	}
+}
+
+func TestKnownCode(t *testing.T) {
+	known := []string{"syntax-error", "type-mismatch", "arg-count", "unknown-pay-code", "unknown-field", "unknown-macro", "unknown-method", "undefined", "use-before-declaration", "unused-var"}
+	for _, code := range known {
+		if !KnownCode(code) {
+			t.Errorf("%s: have unknown, want known", code)
+		}
+	}
+	if KnownCode("no-such-code") {
+		t.Error("no-such-code: have known, want unknown")
+	}
+}
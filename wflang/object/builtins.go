@@ -1,12 +1,14 @@
 package object
 
 import (
+	_ "embed"
+	"encoding/json"
 	"log/slog"
+	"maps"
 	"strings"
+	"sync"
 
 	"github.com/scatternoodle/wflang/internal/lsp"
-	"github.com/scatternoodle/wflang/server/docstring"
-	"github.com/scatternoodle/wflang/wflang/types"
 )
 
 // Builtin wraps a simple map lookup against Builtins(), making the check
@@ -18,131 +20,31 @@ func Builtin(name string) (b Function, ok bool) {
 	return
 }
 
+// Builtins returns all builtin functions keyed by their lowercase name. The map is
+// a copy, and can be modified by the caller.
 func Builtins() map[string]Function {
-	return map[string]Function{
-		If: {
-			Name:       If,
-			ReturnType: types.T_BOOL,
-			Params: []Param{
-				{Name: "condition", Types: pTypes{types.T_BOOL}},
-				{Name: "then", Types: pTypes{types.T_ANY}},
-				{Name: "else", Types: pTypes{types.T_ANY}},
-			},
-		},
-
-		Min: {
-			Name:       Min,
-			ReturnType: types.T_NUMBER,
-			Params: []Param{
-				{Name: "args", Types: pTypes{types.T_NUMBER}, List: true},
-			},
-		},
-
-		Max: {
-			Name:       Max,
-			ReturnType: types.T_NUMBER,
-			Params: []Param{
-				{Name: "args", Types: pTypes{types.T_NUMBER}, List: true},
-			},
-		},
-
-		Contains: {
-			Name:       Contains,
-			ReturnType: types.T_BOOL,
-			Params: []Param{
-				{Name: "x", Types: pTypes{types.T_STRING}},
-				{Name: "y", Types: pTypes{types.T_STRING}},
-			},
-		},
-
-		Sum: {
-			Name:       Sum,
-			ReturnType: types.T_NUMBER,
-			Params: []Param{
-				{Name: "interval", Types: pTypes{types.T_DAY, types.T_WEEK, types.T_PERIOD}},
-				{Name: "range", Types: pTypes{types.T_DAY, types.T_WEEK, types.T_PERIOD, types.T_DATERNG}},
-				{Name: "aliasName", Types: pTypes{types.T_STRING}, Optional: true},
-				{Name: "expression", Types: pTypes{types.T_NUMBER}},
-				{Name: "condition", Types: pTypes{types.T_BOOL}, Optional: true},
-			},
-		},
+	return maps.Clone(catalogue())
+}
 
-		Count: {
-			Name:       Count,
-			ReturnType: types.T_NUMBER,
-			Params: []Param{
-				{Name: "interval", Types: pTypes{types.T_DAY, types.T_WEEK, types.T_PERIOD}},
-				{Name: "range", Types: pTypes{types.T_DAY, types.T_WEEK, types.T_PERIOD, types.T_DATERNG}},
-				{Name: "aliasName", Types: pTypes{types.T_STRING}, Optional: true},
-				{Name: "condition", Types: pTypes{types.T_BOOL}},
-			},
-		},
+// builtinsJSON is the catalogue of builtin functions, and the single source of
+// their signatures and documentation.
+//
+//go:embed builtins.json
+var builtinsJSON []byte
 
-		SumTime:                    {Name: SumTime, ReturnType: types.T_NUMBER},
-		CountTime:                  {Name: CountTime, ReturnType: types.T_NUMBER},
-		FindFirstTime:              {Name: FindFirstTime, ReturnType: types.T_NUMBER},
-		SumSchedule:                {Name: SumSchedule, ReturnType: types.T_NUMBER},
-		CountSchedule:              {Name: CountSchedule, ReturnType: types.T_NUMBER},
-		FindFirstSchedule:          {Name: FindFirstSchedule, ReturnType: types.T_SCHEDREC},
-		CountException:             {Name: CountException, ReturnType: types.T_NUMBER},
-		FindFirstTorDetail:         {Name: FindFirstTorDetail, ReturnType: types.T_TORDTL},
-		FindFirstDayForward:        {Name: FindFirstDayForward, ReturnType: types.T_DATE},
-		FindFirstDayBackward:       {Name: FindFirstDayBackward, ReturnType: types.T_DATE},
-		FindFirstDeletedTime:       {Name: FindFirstDeletedTime, ReturnType: types.T_DATE},
-		LongestConsecutiveRange:    {Name: LongestConsecutiveRange, ReturnType: types.T_DATERNG},
-		FirstConsecutiveDay:        {Name: FirstConsecutiveDay, ReturnType: types.T_DATE},
-		LastConsecutiveDay:         {Name: LastConsecutiveDay, ReturnType: types.T_DATE},
-		FindNthTime:                {Name: FindNthTime, ReturnType: types.T_TIME},
-		Accrued:                    {Name: Accrued, ReturnType: types.T_NUMBER},
-		BalanceAccruedBefore:       {Name: BalanceAccruedBefore, ReturnType: types.T_NUMBER},
-		Balance:                    {Name: Balance, ReturnType: types.T_NUMBER},
-		CallSql:                    {Name: CallSql, ReturnType: types.T_RESULTSET},
-		ConvertDttmByTimezone:      {Name: ConvertDttmByTimezone, ReturnType: types.T_DTTM},
-		CountGroupCalc:             {Name: CountGroupCalc, ReturnType: types.T_NUMBER},
-		CountHolidays:              {Name: CountHolidays, ReturnType: types.T_NUMBER},
-		GetHoliday:                 {Name: GetHoliday, ReturnType: types.T_STRING},
-		CountHomeCrewMembers:       {Name: CountHomeCrewMembers, ReturnType: types.T_NUMBER},
-		CountShiftChanges:          {Name: CountShiftChanges, ReturnType: types.T_NUMBER},
-		EmployeeAttributeExists:    {Name: EmployeeAttributeExists, ReturnType: types.T_BOOL},
-		EmployeeAttribute:          {Name: EmployeeAttribute, ReturnType: types.T_EMPATTR},
-		GetAttributeCalcDate:       {Name: GetAttributeCalcDate, ReturnType: types.T_DATE},
-		GetBooleanFieldFromTor:     {Name: GetBooleanFieldFromTor, ReturnType: types.T_BOOL},
-		GetDateFieldFromTor:        {Name: GetDateFieldFromTor, ReturnType: types.T_DATE},
-		GetNumberFieldFromTor:      {Name: GetNumberFieldFromTor, ReturnType: types.T_NUMBER},
-		GetPayCurrencyCode:         {Name: GetPayCurrencyCode, ReturnType: types.T_STRING},
-		GetSelectFieldValueFromTor: {Name: GetSelectFieldValueFromTor, ReturnType: types.T_STRING},
-		GetStringFieldFromTor:      {Name: GetStringFieldFromTor, ReturnType: types.T_STRING},
-		GetSysDateByTimezone:       {Name: GetSysDateByTimezone, ReturnType: types.T_IDENT},
-		LdLookup:                   {Name: LdLookup, ReturnType: types.T_LDREC},
-		LdValidate:                 {Name: LdValidate, ReturnType: types.T_BOOL},
-		IndexOf:                    {Name: IndexOf, ReturnType: types.T_NUMBER},
-		LengthOfService:            {Name: LengthOfService, ReturnType: types.T_NUMBER},
-		MakeDate:                   {Name: MakeDate, ReturnType: types.T_DATE},
-		MakeDateTime:               {Name: MakeDateTime, ReturnType: types.T_DTTM},
-		MakeDateTimeRange:          {Name: MakeDateTimeRange, ReturnType: types.T_DTTMRNG},
-		PayCodeInScheduleMap:       {Name: PayCodeInScheduleMap, ReturnType: types.T_BOOL},
-		PayCodeInTimeSheetMap:      {Name: PayCodeInTimeSheetMap, ReturnType: types.T_BOOL},
-		RangeLookup:                {Name: RangeLookup, ReturnType: types.T_NUMBER},
-		Round:                      {Name: Round, ReturnType: types.T_NUMBER},
-		RoundUp:                    {Name: RoundUp, ReturnType: types.T_NUMBER},
-		RoundDown:                  {Name: RoundDown, ReturnType: types.T_NUMBER},
-		RoundToInt:                 {Name: RoundToInt, ReturnType: types.T_NUMBER},
-		SemiMonthlyPeriod:          {Name: SemiMonthlyPeriod, ReturnType: types.T_PERIOD},
-		Substr:                     {Name: Substr, ReturnType: types.T_STRING},
-		ToLowerCase:                {Name: ToLowerCase, ReturnType: types.T_STRING},
-		ToUpperCase:                {Name: ToUpperCase, ReturnType: types.T_STRING},
-		MinSchedule:                {Name: MinSchedule, ReturnType: types.T_NUMBER},
-		MaxSchedule:                {Name: MaxSchedule, ReturnType: types.T_NUMBER},
-		AvgSchedule:                {Name: AvgSchedule, ReturnType: types.T_NUMBER},
-		MinTime:                    {Name: MinTime, ReturnType: types.T_NUMBER},
-		MaxTime:                    {Name: MaxTime, ReturnType: types.T_NUMBER},
-		AvgTime:                    {Name: AvgTime, ReturnType: types.T_NUMBER},
-		SumException:               {Name: SumException, ReturnType: types.T_NUMBER},
-		MinException:               {Name: MinException, ReturnType: types.T_NUMBER},
-		MaxException:               {Name: MaxException, ReturnType: types.T_NUMBER},
-		AvgException:               {Name: AvgException, ReturnType: types.T_NUMBER},
+// catalogue parses builtinsJSON once. The catalogue is embedded, so a malformed
+// one is a programming error and panics.
+var catalogue = sync.OnceValue(func() map[string]Function {
+	var fns []Function
+	if err := json.Unmarshal(builtinsJSON, &fns); err != nil {
+		panic("object: parsing builtins.json: " + err.Error())
 	}
-}
+	m := make(map[string]Function, len(fns))
+	for _, fn := range fns {
+		m[strings.ToLower(fn.Name)] = fn
+	}
+	return m
+})
 
 const (
 	If                         string = "if"
@@ -216,154 +118,15 @@ const (
 	AvgException               string = "averageexception"
 )
 
+// DocMarkdown returns the markdown documentation of the builtin with the given
+// name, which is empty if there is no such builtin.
 func DocMarkdown(name string) *lsp.MarkupContent {
 	content := lsp.MarkupContent{
 		Kind:  lsp.MarkupKindMarkdown,
 		Value: "",
 	}
-	if doc, ok := builtinDocString(name); ok {
-		content.Value = doc
-	}
-	if name == Contains {
-		doc, ok := docstring.BuiltinDoc(name)
-		if ok {
-			content.Value = doc.String()
-		}
+	if fn, ok := Builtin(name); ok {
+		content.Value = fn.Doc().String()
 	}
 	return &content
 }
-
-func builtinDocString(name string) (text string, ok bool) {
-	switch name {
-	case Min:
-		return docstring.Min, true
-	case Max:
-		return docstring.Max, true
-	// case Contains:
-	// 	return docstring.Contains, true
-	case Sum:
-		return docstring.Sum, true
-	case Count:
-		return docstring.Count, true
-	case If:
-		return docstring.If, true
-	case SumTime:
-		return docstring.SumTime, true
-	case CountTime:
-		return docstring.CountTime, true
-	case FindFirstTime:
-		return docstring.FindFirstTime, true
-	case SumSchedule:
-		return docstring.SumSchedule, true
-	case CountSchedule:
-		return docstring.CountSchedule, true
-	case FindFirstSchedule:
-		return docstring.FindFirstSchedule, true
-	case CountException:
-		return docstring.CountException, true
-	case FindFirstTorDetail:
-		return docstring.FindFirstTorDetail, true
-	case FindFirstDayForward:
-		return docstring.FindFirstDayForward, true
-	case FindFirstDayBackward:
-		return docstring.FindFirstDayBackward, true
-	case FindFirstDeletedTime:
-		return docstring.FindFirstDeletedTime, true
-	case LongestConsecutiveRange:
-		return docstring.LongestConsecutiveRange, true
-	case FirstConsecutiveDay:
-		return docstring.FirstConsecutiveDay, true
-	case LastConsecutiveDay:
-		return docstring.LastConsecutiveDay, true
-	case FindNthTime:
-		return docstring.FindNthTime, true
-	case Accrued:
-		return docstring.Accrued, true
-	case BalanceAccruedBefore:
-		return docstring.BalanceAccruedBefore, true
-	case ConvertDttmByTimezone:
-		return docstring.ConvertDttmByTimezone, true
-	case CountHolidays:
-		return docstring.CountHolidays, true
-	case GetHoliday:
-		return docstring.GetHoliday, true
-	case EmployeeAttributeExists:
-		return docstring.Employee_attribute_exists, true
-	case EmployeeAttribute:
-		return docstring.Employee_attribute, true
-	case GetAttributeCalcDate:
-		return docstring.GetAttributeCalculationDate, true
-	case GetBooleanFieldFromTor:
-		return docstring.GetBooleanFieldFromTor, true
-	case GetDateFieldFromTor:
-		return docstring.GetDateFieldFromTor, true
-	case GetSelectFieldValueFromTor:
-		return docstring.GetSelectFieldValueFromTor, true
-	case GetNumberFieldFromTor:
-		return docstring.GetNumberFieldFromTor, true
-	case GetStringFieldFromTor:
-		return docstring.GetStringValueFromTor, true
-	case GetSysDateByTimezone:
-		return docstring.GetSysDateByTimezone, true
-	case LdLookup:
-		return docstring.LDLookup, true
-	case LdValidate:
-		return docstring.LDValidate, true
-	case IndexOf:
-		return docstring.IndexOf, true
-	case GetPayCurrencyCode:
-		return docstring.GetPayCurrencyCode, true
-	case CallSql:
-		return docstring.CallSQL, true
-	case LengthOfService:
-		return docstring.LengthOfService, true
-	case MakeDate:
-		return docstring.MakeDate, true
-	case MakeDateTime:
-		return docstring.MakeDateTime, true
-	case MakeDateTimeRange:
-		return docstring.MakeDateTimeRange, true
-	case PayCodeInScheduleMap:
-		return docstring.PayCodeInScheduleMap, true
-	case PayCodeInTimeSheetMap:
-		return docstring.PayCodeInTimeSheetMap, true
-	case Round:
-		return docstring.Round, true
-	case RoundDown:
-		return docstring.RoundDown, true
-	case RoundUp:
-		return docstring.RoundUp, true
-	case RoundToInt:
-		return docstring.RoundToInt, true
-	case SemiMonthlyPeriod:
-		return docstring.SemiMonthlyPeriod, true
-	case Substr:
-		return docstring.Substr, true
-	case ToLowerCase:
-		return docstring.ToLowerCase, true
-	case ToUpperCase:
-		return docstring.ToUpperCase, true
-	case MinSchedule:
-		return docstring.MinSchedule, true
-	case MaxSchedule:
-		return docstring.MaxSchedule, true
-	case AvgSchedule:
-		return docstring.AvgSchedule, true
-	case MinTime:
-		return docstring.MinTime, true
-	case MaxTime:
-		return docstring.MaxTime, true
-	case AvgTime:
-		return docstring.AvgTime, true
-	case SumException:
-		return docstring.SumException, true
-	case MinException:
-		return docstring.MinException, true
-	case MaxException:
-		return docstring.MaxException, true
-	case AvgException:
-		return docstring.AvgException, true
-	}
-
-	return "", false
-}
@@ -0,0 +1,133 @@
+package object
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestBuiltinsCatalogue(t *testing.T) {
+	names := []string{
+		If, Min, Max, Contains, Sum, Count, SumTime, CountTime, FindFirstTime, SumSchedule, CountSchedule,
+		FindFirstSchedule, CountException, FindFirstTorDetail, FindFirstDayForward, FindFirstDayBackward,
+		FindFirstDeletedTime, LongestConsecutiveRange, FirstConsecutiveDay, LastConsecutiveDay, FindNthTime,
+		Accrued, BalanceAccruedBefore, Balance, CallSql, ConvertDttmByTimezone, CountGroupCalc, CountHolidays,
+		GetHoliday, CountHomeCrewMembers, CountShiftChanges, EmployeeAttributeExists, EmployeeAttribute,
+		GetAttributeCalcDate, GetBooleanFieldFromTor, GetDateFieldFromTor, GetNumberFieldFromTor,
+		GetPayCurrencyCode, GetSelectFieldValueFromTor, GetStringFieldFromTor, GetSysDateByTimezone, LdLookup,
+		LdValidate, IndexOf, LengthOfService, MakeDate, MakeDateTime, MakeDateTimeRange, PayCodeInScheduleMap,
+		PayCodeInTimeSheetMap, RangeLookup, Round, RoundUp, RoundDown, RoundToInt, SemiMonthlyPeriod, Substr,
+		ToLowerCase, ToUpperCase, MinSchedule, MaxSchedule, AvgSchedule, MinTime, MaxTime, AvgTime, SumException,
+		MinException, MaxException, AvgException,
+	}
+	if len(Builtins()) != len(names) {
+		t.Errorf("catalogue len: have %d, want %d", len(Builtins()), len(names))
+	}
+	for _, name := range names {
+		if _, ok := Builtin(name); !ok {
+			t.Errorf("builtin %s missing from catalogue", name)
+		}
+	}
+
+	valid := []types.Type{
+		types.T_ANY, types.T_NUMBER, types.T_STRING, types.T_IDENT, types.T_TIME, types.T_DTTM, types.T_DTTMRNG,
+		types.T_DATE, types.T_DATERNG, types.T_BOOL, types.T_SCHEDREC, types.T_TIMEREC, types.T_EMPATTR,
+		types.T_LDREC, types.T_TORDTL, types.T_RESULTSET, types.T_TRGROUP, types.T_EXCEPTION, types.T_DAY,
+		types.T_WEEK, types.T_PERIOD,
+	}
+	clauses := []string{"", ClauseBy, ClauseOver, ClauseAlias, ClauseWhere, ClauseOrderBy}
+	// builtins whose signatures are not yet known, as listed in docs/hovertext_support.md.
+	unknown := []string{Balance, CountGroupCalc, CountHomeCrewMembers, CountShiftChanges, RangeLookup}
+
+	for name, fn := range Builtins() {
+		if strings.ToLower(fn.Name) != name {
+			t.Errorf("%s: key does not match name %s", name, fn.Name)
+		}
+		if !slices.Contains(valid, fn.ReturnType) {
+			t.Errorf("%s: invalid return type %q", name, fn.ReturnType)
+		}
+		if fn.Desc == "" {
+			t.Errorf("%s: missing description", name)
+		}
+		if (len(fn.Params) == 0) != slices.Contains(unknown, name) {
+			t.Errorf("%s: have %d params, want params unless its signature is unknown", name, len(fn.Params))
+		}
+		if fn.Records != "" && !slices.Contains(RecordTypes(), fn.Records) {
+			t.Errorf("%s: invalid records type %q", name, fn.Records)
+		}
+		hasAlias := slices.ContainsFunc(fn.Params, func(p Param) bool { return p.Clause == ClauseAlias })
+		if fn.Records != "" && !hasAlias {
+			t.Errorf("%s: iterates records but has no alias", name)
+		}
+		for i, param := range fn.Params {
+			if len(param.Types) == 0 {
+				t.Errorf("%s: param %s has no types", name, param.Name)
+			}
+			for _, pt := range param.Types {
+				if !slices.Contains(valid, pt) {
+					t.Errorf("%s: param %s has invalid type %q", name, param.Name, pt)
+				}
+			}
+			if !slices.Contains(clauses, param.Clause) {
+				t.Errorf("%s: param %s has invalid clause %q", name, param.Name, param.Clause)
+			}
+			if param.PairA && (i+1 == len(fn.Params) || !fn.Params[i+1].PairB) {
+				t.Errorf("%s: param %s is not followed by the second of its pair", name, param.Name)
+			}
+			if param.Clause == ClauseAlias && (i == 0 || fn.Params[i-1].Clause != ClauseOver) {
+				t.Errorf("%s: alias param %s does not follow an over clause", name, param.Name)
+			}
+		}
+	}
+}
+
+func TestFunctionDoc(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantSig    string
+		wantRet    string
+		wantLabels []string
+	}{
+		{Contains, "contains(x: string, y: string)", "boolean", []string{"x: string", "y: string"}},
+		{Min, "min(args: number...)", "number", []string{"args: number..."}},
+		{
+			Count,
+			"count(by interval: day|week|period, over range: day|week|period|dateRange alias aliasName?: string, where condition: boolean)",
+			"number",
+			[]string{
+				"by interval: day|week|period",
+				"over range: day|week|period|dateRange",
+				"alias aliasName?: string",
+				"where condition: boolean",
+			},
+		},
+		{GetDateFieldFromTor, "getDateFieldFromTor(torId: string, fieldId: string)", "date|null", []string{"torId: string", "fieldId: string"}},
+		{Balance, "balance(...)", "number", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, ok := Builtin(tt.name)
+			if !ok {
+				t.Fatalf("builtin %s not found", tt.name)
+			}
+			doc := fn.Doc()
+			if doc.Signature != tt.wantSig {
+				t.Errorf("signature: have %q, want %q", doc.Signature, tt.wantSig)
+			}
+			if doc.Returns != tt.wantRet {
+				t.Errorf("returns: have %q, want %q", doc.Returns, tt.wantRet)
+			}
+			if len(doc.Params) != len(tt.wantLabels) {
+				t.Fatalf("params: have %d, want %d", len(doc.Params), len(tt.wantLabels))
+			}
+			for i, param := range doc.Params {
+				if label := doc.Signature[param.Label[0]:param.Label[1]]; label != tt.wantLabels[i] {
+					t.Errorf("param %d label: have %q, want %q", i, label, tt.wantLabels[i])
+				}
+			}
+		})
+	}
+}
@@ -1,20 +1,100 @@
 package object
 
-import "github.com/scatternoodle/wflang/wflang/types"
+import (
+	"strings"
 
+	"github.com/scatternoodle/wflang/server/docstring"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// Function describes a builtin function. Builtins are loaded from the catalogue in
+// builtins.json, the fields of which are given by the json tags.
 type Function struct {
-	Name       string
-	ReturnType types.Type
-	Params     []Param
+	Name       string     `json:"name"`
+	ReturnType types.Type `json:"returns"`
+	Nullable   bool       `json:"nullable,omitempty"` // can return null
+	Records    types.Type `json:"records,omitempty"`  // the type of record iterated by summary functions, and so bound to their alias
+	Params     []Param    `json:"params,omitempty"`   // nil if the signature is not yet known
+	Desc       string     `json:"description"`        // markdown
+	Examples   []string   `json:"examples,omitempty"`
+	Deprecated string     `json:"deprecated,omitempty"` // reason for deprecation, empty if not deprecated
 }
 
-type pTypes []types.Type
-
 type Param struct {
-	Name     string
-	Types    []types.Type // permitted types, can be many for some params
-	Optional bool
-	List     bool // function call can have N number of this param
-	PairA    bool // is 1st in pair of params
-	PairB    bool // is 2nd in pair of params
+	Name     string       `json:"name"`
+	Types    []types.Type `json:"types"` // permitted types, can be many for some params
+	Optional bool         `json:"optional,omitempty"`
+	List     bool         `json:"list,omitempty"`   // function call can have N number of this param
+	PairA    bool         `json:"pairA,omitempty"`  // is 1st in pair of params
+	PairB    bool         `json:"pairB,omitempty"`  // is 2nd in pair of params
+	Clause   string       `json:"clause,omitempty"` // keyword introducing the param in summary functions, see Clause consts
+	Desc     string       `json:"desc,omitempty"`   // markdown
+}
+
+// Clauses of summary function arguments. A param with no clause is a plain
+// expression.
+const (
+	ClauseBy      string = "by"
+	ClauseOver    string = "over"
+	ClauseAlias   string = "alias" // part of the over clause, not an argument of its own.
+	ClauseWhere   string = "where"
+	ClauseOrderBy string = "order by"
+)
+
+// Doc returns the documentation for f, with a signature generated from its params.
+func (f Function) Doc() *docstring.FunctionDoc {
+	doc := &docstring.FunctionDoc{
+		Name:       f.Name,
+		Returns:    string(f.ReturnType),
+		Desc:       f.Desc,
+		Examples:   f.Examples,
+		Deprecated: f.Deprecated,
+	}
+	if f.Nullable {
+		doc.Returns += "|" + string(types.T_NULL)
+	}
+	if f.Params == nil {
+		doc.Signature = f.Name + "(...)"
+		return doc
+	}
+
+	sig := f.Name + "("
+	for i, param := range f.Params {
+		switch {
+		case param.Clause == ClauseAlias:
+			sig += " "
+		case i > 0:
+			sig += ", "
+		}
+		start := len(sig)
+		if param.Clause != "" {
+			sig += param.Clause + " "
+		}
+		sig += param.Name
+		if param.Optional {
+			sig += "?"
+		}
+		sig += ": " + joinTypes(param.Types)
+		if param.List {
+			sig += "..."
+		}
+		doc.Params = append(doc.Params, &docstring.ParamDoc{
+			Name:     param.Name,
+			Label:    [2]int{start, len(sig)},
+			Type:     joinTypes(param.Types),
+			Desc:     param.Desc,
+			Optional: param.Optional,
+			Inline:   param.Clause == ClauseAlias,
+		})
+	}
+	doc.Signature = sig + ")"
+	return doc
+}
+
+func joinTypes(ts []types.Type) string {
+	s := make([]string, len(ts))
+	for i, t := range ts {
+		s[i] = string(t)
+	}
+	return strings.Join(s, "|")
 }
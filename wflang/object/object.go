@@ -136,39 +136,12 @@ func (b Boolean) Type() types.Type        { return types.T_BOOL }
 func (b Boolean) Methods() []Function     { return nil }
 func (b Boolean) Value() (v any, ok bool) { return b.Val, b.Static }
 
-type ScheduleRecord struct{}
-
-func (s ScheduleRecord) Type() types.Type        { return types.T_SCHEDREC }
-func (s ScheduleRecord) Methods() []Function     { return nil }
-func (s ScheduleRecord) Value() (v any, ok bool) { return nil, false }
-
-type TimeRecord struct{}
-
-func (t TimeRecord) Type() types.Type        { return types.T_TIMEREC }
-func (t TimeRecord) Methods() []Function     { return nil }
-func (t TimeRecord) Value() (v any, ok bool) { return nil, false }
-
-type Attribute struct {
-	Name    string
-	AttType types.Type
-}
-
-func (a Attribute) Type() types.Type        { return types.T_EMPATTR }
-func (a Attribute) Methods() []Function     { return nil }
-func (a Attribute) Value() (v any, ok bool) { return nil, false }
-
 type LDRecord struct{}
 
 func (l LDRecord) Type() types.Type        { return types.T_LDREC }
 func (l LDRecord) Methods() []Function     { return nil }
 func (l LDRecord) Value() (v any, ok bool) { return nil, false }
 
-type TORDetailRecord struct{}
-
-func (t TORDetailRecord) Type() types.Type        { return types.T_TORDTL }
-func (t TORDetailRecord) Methods() []Function     { return nil }
-func (t TORDetailRecord) Value() (v any, ok bool) { return nil, false }
-
 type ResultSet struct {
 	Columns []struct {
 		Name     string
@@ -186,12 +159,6 @@ func (t TimeRecordGroup) Type() types.Type        { return types.T_TRGROUP }
 func (t TimeRecordGroup) Methods() []Function     { return nil }
 func (t TimeRecordGroup) Value() (v any, ok bool) { return nil, false }
 
-type Exception struct{}
-
-func (s Exception) Type() types.Type        { return types.T_EXCEPTION }
-func (s Exception) Methods() []Function     { return nil }
-func (s Exception) Value() (v any, ok bool) { return nil, false }
-
 type Day struct{}
 
 func (d Day) Type() types.Type        { return types.T_DAY }
@@ -221,3 +188,55 @@ type Any struct{}
 func (a Any) Type() types.Type        { return types.T_ANY }
 func (a Any) Methods() []Function     { return nil }
 func (a Any) Value() (v any, ok bool) { return nil, false }
+
+// FromType returns the zero-value, non-static Object of type t. Types without a
+// corresponding Object resolve to Undefined.
+func FromType(t types.Type) Object {
+	switch t {
+	case types.T_ANY:
+		return Any{}
+	case types.T_NUMBER:
+		return Number{}
+	case types.T_STRING:
+		return String{}
+	case types.T_IDENT:
+		return Ident{}
+	case types.T_TIME:
+		return Time{}
+	case types.T_DTTM:
+		return DateTime{}
+	case types.T_DTTMRNG:
+		return DateTimeRange{}
+	case types.T_DATE:
+		return Date{}
+	case types.T_DATERNG:
+		return DateRange{}
+	case types.T_BOOL:
+		return Boolean{}
+	case types.T_SCHEDREC:
+		return ScheduleRecord{}
+	case types.T_TIMEREC:
+		return TimeRecord{}
+	case types.T_EMPATTR:
+		return Attribute{}
+	case types.T_LDREC:
+		return LDRecord{}
+	case types.T_TORDTL:
+		return TORDetailRecord{}
+	case types.T_RESULTSET:
+		return ResultSet{}
+	case types.T_TRGROUP:
+		return TimeRecordGroup{}
+	case types.T_EXCEPTION:
+		return Exception{}
+	case types.T_DAY:
+		return Day{}
+	case types.T_WEEK:
+		return Week{}
+	case types.T_PERIOD:
+		return Period{}
+	case types.T_NULL:
+		return Null{}
+	}
+	return Undefined{}
+}
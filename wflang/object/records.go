@@ -0,0 +1,181 @@
+package object
+
+import (
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// Record is implemented by objects with named fields, such as time records. Fields
+// are accessed with member expressions, e.g. x.HOURS, and the methods described
+// by RecordMethods are called with them, e.g. x.hasTimes().
+type Record interface {
+	Object
+	WorkDate() time.Time              // the day the record belongs to.
+	Field(name string) (Object, bool) // matched case-insensitively.
+}
+
+// Standard record field names. Records can also carry custom fields, which are
+// matched after these.
+const (
+	FieldWorkDate  string = "WORK_DT"
+	FieldPayCode   string = "PAY_CODE"
+	FieldHours     string = "HOURS"
+	FieldStartDttm string = "START_DTTM"
+	FieldEndDttm   string = "END_DTTM"
+	FieldWorkRule  string = "WORK_RULE"
+	FieldCode      string = "EXCEPTION_CODE"
+	FieldSeverity  string = "SEVERITY"
+	FieldMessage   string = "MESSAGE"
+	FieldTorID     string = "TOR_ID"
+	FieldStatus    string = "STATUS"
+)
+
+// TimeRecord is a slice of time worked or taken by an employee on a day.
+type TimeRecord struct {
+	Date     time.Time
+	PayCode  string
+	Hours    float64
+	Start    time.Time // zero if the record has no start time.
+	End      time.Time // zero if the record has no end time.
+	WorkRule string
+	Fields   map[string]Object
+}
+
+func (t TimeRecord) Type() types.Type        { return types.T_TIMEREC }
+func (t TimeRecord) Methods() []Function     { return RecordMethods()[types.T_TIMEREC] }
+func (t TimeRecord) Value() (v any, ok bool) { return nil, false }
+func (t TimeRecord) WorkDate() time.Time     { return t.Date }
+
+func (t TimeRecord) Field(name string) (Object, bool) {
+	return field(name, t.Fields, map[string]Object{
+		FieldWorkDate:  Date{Val: t.Date, Static: true},
+		FieldPayCode:   String{Val: t.PayCode, Static: true},
+		FieldHours:     Number{Val: t.Hours, Static: true},
+		FieldStartDttm: dateTimeOrNull(t.Start),
+		FieldEndDttm:   dateTimeOrNull(t.End),
+		FieldWorkRule:  String{Val: t.WorkRule, Static: true},
+	})
+}
+
+// ScheduleRecord is a slice of time an employee is scheduled to work on a day.
+type ScheduleRecord struct {
+	Date     time.Time
+	PayCode  string
+	Hours    float64
+	Start    time.Time // zero if the record has no start time.
+	End      time.Time // zero if the record has no end time.
+	WorkRule string
+	Fields   map[string]Object
+}
+
+func (s ScheduleRecord) Type() types.Type        { return types.T_SCHEDREC }
+func (s ScheduleRecord) Methods() []Function     { return RecordMethods()[types.T_SCHEDREC] }
+func (s ScheduleRecord) Value() (v any, ok bool) { return nil, false }
+func (s ScheduleRecord) WorkDate() time.Time     { return s.Date }
+
+func (s ScheduleRecord) Field(name string) (Object, bool) {
+	return field(name, s.Fields, map[string]Object{
+		FieldWorkDate:  Date{Val: s.Date, Static: true},
+		FieldPayCode:   String{Val: s.PayCode, Static: true},
+		FieldHours:     Number{Val: s.Hours, Static: true},
+		FieldStartDttm: dateTimeOrNull(s.Start),
+		FieldEndDttm:   dateTimeOrNull(s.End),
+		FieldWorkRule:  String{Val: s.WorkRule, Static: true},
+	})
+}
+
+// Exception is raised against an employee's timesheet on a day, e.g. for a late
+// punch.
+type Exception struct {
+	Date     time.Time
+	Code     string
+	Severity string
+	Message  string
+	Fields   map[string]Object
+}
+
+func (e Exception) Type() types.Type        { return types.T_EXCEPTION }
+func (e Exception) Methods() []Function     { return RecordMethods()[types.T_EXCEPTION] }
+func (e Exception) Value() (v any, ok bool) { return nil, false }
+func (e Exception) WorkDate() time.Time     { return e.Date }
+
+func (e Exception) Field(name string) (Object, bool) {
+	return field(name, e.Fields, map[string]Object{
+		FieldWorkDate: Date{Val: e.Date, Static: true},
+		FieldCode:     String{Val: e.Code, Static: true},
+		FieldSeverity: String{Val: e.Severity, Static: true},
+		FieldMessage:  String{Val: e.Message, Static: true},
+	})
+}
+
+// TORDetailRecord is a day of a Time Off Request. Fields holds the values of the
+// request's TOR fields, keyed by policy ID.
+type TORDetailRecord struct {
+	TorID   string
+	Date    time.Time
+	PayCode string
+	Hours   float64
+	Status  string
+	Fields  map[string]Object
+}
+
+func (t TORDetailRecord) Type() types.Type        { return types.T_TORDTL }
+func (t TORDetailRecord) Methods() []Function     { return RecordMethods()[types.T_TORDTL] }
+func (t TORDetailRecord) Value() (v any, ok bool) { return nil, false }
+func (t TORDetailRecord) WorkDate() time.Time     { return t.Date }
+
+func (t TORDetailRecord) Field(name string) (Object, bool) {
+	return field(name, t.Fields, map[string]Object{
+		FieldTorID:    String{Val: t.TorID, Static: true},
+		FieldWorkDate: Date{Val: t.Date, Static: true},
+		FieldPayCode:  String{Val: t.PayCode, Static: true},
+		FieldHours:    Number{Val: t.Hours, Static: true},
+		FieldStatus:   String{Val: t.Status, Static: true},
+	})
+}
+
+// Attribute is the value of an Employee Attribute over the days it is effective.
+type Attribute struct {
+	Name    string
+	AttType types.Type
+	Val     Object    // of type AttType.
+	Start   time.Time // zero if effective from the start of time.
+	End     time.Time // zero if effective until the end of time.
+}
+
+func (a Attribute) Type() types.Type    { return types.T_EMPATTR }
+func (a Attribute) Methods() []Function { return nil }
+
+func (a Attribute) Value() (v any, ok bool) {
+	if a.Val == nil {
+		return nil, false
+	}
+	return a.Val.Value()
+}
+
+// EffectiveOn returns true if the attribute is effective on day.
+func (a Attribute) EffectiveOn(day time.Time) bool {
+	return (a.Start.IsZero() || !day.Before(a.Start)) && (a.End.IsZero() || !day.After(a.End))
+}
+
+// field looks name up in the standard fields of a record, then its custom fields.
+func field(name string, custom, standard map[string]Object) (Object, bool) {
+	if obj, ok := standard[strings.ToUpper(name)]; ok {
+		return obj, true
+	}
+	for k, v := range custom {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func dateTimeOrNull(t time.Time) Object {
+	if t.IsZero() {
+		return Null{}
+	}
+	return DateTime{Val: t, Static: true}
+}
@@ -0,0 +1,161 @@
+package object
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// FieldSchema describes a field of a record, accessed with a member expression on
+// the alias of a summary function, e.g. x.HOURS.
+type FieldSchema struct {
+	Name string     `json:"name"`
+	Type types.Type `json:"type"`
+	Desc string     `json:"description,omitempty"` // markdown
+}
+
+// Schemas holds the fields of each type of record, keyed by the record type. The
+// methods of records are fixed, and described by RecordMethods instead.
+type Schemas map[types.Type][]FieldSchema
+
+// RecordTypes returns the types of record that have schemas.
+func RecordTypes() []types.Type {
+	return []types.Type{types.T_TIMEREC, types.T_SCHEDREC, types.T_EXCEPTION, types.T_TORDTL}
+}
+
+// fieldTypes are the types that fields of a record may have.
+var fieldTypes = []types.Type{types.T_NUMBER, types.T_STRING, types.T_DATE, types.T_TIME, types.T_DTTM, types.T_BOOL}
+
+// DefaultSchemas returns the standard fields of each type of record, which match
+// the fields of TimeRecord, ScheduleRecord, Exception and TORDetailRecord.
+func DefaultSchemas() Schemas {
+	slice := func(kind string) []FieldSchema {
+		return []FieldSchema{
+			{FieldWorkDate, types.T_DATE, "the day the " + kind + " belongs to."},
+			{FieldPayCode, types.T_STRING, "the pay code of the " + kind + "."},
+			{FieldHours, types.T_NUMBER, "the number of hours of the " + kind + "."},
+			{FieldStartDttm, types.T_DTTM, "the start of the " + kind + ", null if it has none."},
+			{FieldEndDttm, types.T_DTTM, "the end of the " + kind + ", null if it has none."},
+			{FieldWorkRule, types.T_STRING, "the work rule applied to the " + kind + "."},
+		}
+	}
+	return Schemas{
+		types.T_TIMEREC:  slice("time record"),
+		types.T_SCHEDREC: slice("schedule record"),
+		types.T_EXCEPTION: {
+			{FieldWorkDate, types.T_DATE, "the day the exception was raised on."},
+			{FieldCode, types.T_STRING, "the code of the exception."},
+			{FieldSeverity, types.T_STRING, "the severity of the exception."},
+			{FieldMessage, types.T_STRING, "the message shown for the exception."},
+		},
+		types.T_TORDTL: {
+			{FieldTorID, types.T_STRING, "the ID of the Time Off Request."},
+			{FieldWorkDate, types.T_DATE, "the day of the request."},
+			{FieldPayCode, types.T_STRING, "the pay code of the time requested."},
+			{FieldHours, types.T_NUMBER, "the number of hours requested."},
+			{FieldStatus, types.T_STRING, "the status of the request."},
+		},
+	}
+}
+
+// Record method names.
+const (
+	MethodHasTimes   string = "hasTimes"
+	MethodTimeRange  string = "timeRange"
+	MethodIsSeverity string = "isSeverity"
+	MethodHasStatus  string = "hasStatus"
+)
+
+// RecordMethods returns the methods of each type of record, keyed by the record
+// type, called with a member expression on the alias of a summary function, e.g.
+// x.hasTimes(). Unlike fields, they cannot be extended by a project.
+func RecordMethods() map[types.Type][]Function {
+	slice := func(kind string) []Function {
+		return []Function{
+			{Name: MethodHasTimes, ReturnType: types.T_BOOL, Params: []Param{}, Desc: "Returns true if the " + kind + " has both a start and an end time."},
+			{Name: MethodTimeRange, ReturnType: types.T_DTTMRNG, Nullable: true, Params: []Param{}, Desc: "Returns the range from the start to the end of the " + kind + ", or null if it has no times."},
+		}
+	}
+	return map[types.Type][]Function{
+		types.T_TIMEREC:  slice("time record"),
+		types.T_SCHEDREC: slice("schedule record"),
+		types.T_EXCEPTION: {{
+			Name: MethodIsSeverity, ReturnType: types.T_BOOL,
+			Params: []Param{{Name: "severity", Types: []types.Type{types.T_STRING}}},
+			Desc:   "Returns true if the exception has the given severity, ignoring case.",
+		}},
+		types.T_TORDTL: {{
+			Name: MethodHasStatus, ReturnType: types.T_BOOL,
+			Params: []Param{{Name: "status", Types: []types.Type{types.T_STRING}}},
+			Desc:   "Returns true if the request has the given status, ignoring case.",
+		}},
+	}
+}
+
+// RecordMethod returns the method of the record type called name, ignoring case.
+func RecordMethod(record types.Type, name string) (Function, bool) {
+	for _, fn := range RecordMethods()[record] {
+		if strings.EqualFold(fn.Name, name) {
+			return fn, true
+		}
+	}
+	return Function{}, false
+}
+
+// Field returns the field of the record type called name, ignoring case.
+func (s Schemas) Field(record types.Type, name string) (FieldSchema, bool) {
+	for _, f := range s[record] {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return FieldSchema{}, false
+}
+
+// Extend returns a copy of s with the fields of more added, replacing any field of
+// the same record type and name.
+func (s Schemas) Extend(more Schemas) Schemas {
+	ext := maps.Clone(s)
+	if ext == nil {
+		ext = Schemas{}
+	}
+	for record, fields := range more {
+		ext[record] = slices.Clone(ext[record])
+		for _, f := range fields {
+			i := slices.IndexFunc(ext[record], func(have FieldSchema) bool { return strings.EqualFold(have.Name, f.Name) })
+			if i < 0 {
+				ext[record] = append(ext[record], f)
+			} else {
+				ext[record][i] = f
+			}
+		}
+	}
+	return ext
+}
+
+// Validate returns an error if s describes a type that is not a record, or a field
+// without a name or with a type that fields cannot have.
+func (s Schemas) Validate() error {
+	for record, fields := range s {
+		if !slices.Contains(RecordTypes(), record) {
+			return fmt.Errorf("invalid record type %q, want one of %s", record, typeList(RecordTypes()))
+		}
+		for i, f := range fields {
+			if f.Name == "" {
+				return fmt.Errorf("%s field %d has no name", record, i)
+			}
+			if !slices.Contains(fieldTypes, f.Type) {
+				return fmt.Errorf("%s field %s: invalid type %q, want one of %s", record, f.Name, f.Type, typeList(fieldTypes))
+			}
+		}
+	}
+	return nil
+}
+
+// typeList returns ts separated by commas, for error messages.
+func typeList(ts []types.Type) string {
+	return strings.ReplaceAll(joinTypes(ts), "|", ", ")
+}
@@ -0,0 +1,78 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestDefaultSchemas(t *testing.T) {
+	records := []Record{TimeRecord{}, ScheduleRecord{}, Exception{}, TORDetailRecord{}}
+	schemas := DefaultSchemas()
+	if err := schemas.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range records {
+		fields := schemas[rec.Type()]
+		if len(fields) == 0 {
+			t.Errorf("%s: no schema", rec.Type())
+		}
+		for _, f := range fields {
+			obj, ok := rec.Field(f.Name)
+			if !ok {
+				t.Errorf("%s: field %s not on record", rec.Type(), f.Name)
+				continue
+			}
+			if obj.Type() != f.Type && obj.Type() != types.T_NULL {
+				t.Errorf("%s: field %s: have %s, schema says %s", rec.Type(), f.Name, obj.Type(), f.Type)
+			}
+		}
+	}
+}
+
+func TestSchemasExtend(t *testing.T) {
+	base := DefaultSchemas()
+	ext := base.Extend(Schemas{
+		types.T_TIMEREC: {
+			{Name: "DEPARTMENT", Type: types.T_STRING},
+			{Name: "hours", Type: types.T_STRING, Desc: "replaced"},
+		},
+	})
+
+	if f, ok := ext.Field(types.T_TIMEREC, "department"); !ok || f.Type != types.T_STRING {
+		t.Errorf("DEPARTMENT: have %+v %t, want a string field", f, ok)
+	}
+	if f, _ := ext.Field(types.T_TIMEREC, "HOURS"); f.Desc != "replaced" {
+		t.Errorf("HOURS: have %+v, want the replacement", f)
+	}
+	if len(ext[types.T_TIMEREC]) != len(base[types.T_TIMEREC])+1 {
+		t.Errorf("fields: have %d, want %d", len(ext[types.T_TIMEREC]), len(base[types.T_TIMEREC])+1)
+	}
+	if _, ok := base.Field(types.T_TIMEREC, "DEPARTMENT"); ok {
+		t.Error("Extend modified the original schemas")
+	}
+	if _, ok := ext.Field(types.T_SCHEDREC, "DEPARTMENT"); ok {
+		t.Error("DEPARTMENT added to schedule records")
+	}
+}
+
+func TestSchemasValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas Schemas
+		wantErr bool
+	}{
+		{"valid", Schemas{types.T_EXCEPTION: {{Name: "SOURCE", Type: types.T_STRING}}}, false},
+		{"not a record", Schemas{types.T_DAY: {{Name: "X", Type: types.T_STRING}}}, true},
+		{"no name", Schemas{types.T_TIMEREC: {{Type: types.T_STRING}}}, true},
+		{"invalid type", Schemas{types.T_TIMEREC: {{Name: "X", Type: "text"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schemas.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("have %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
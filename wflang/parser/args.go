@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// checkArgs validates the arguments of call against the params of fn, given the
+// resolved objects of each argument. Errors are reported against the offending
+// argument, or the closing parenthesis of call if an argument is missing.
+//
+// Functions without params have no known signature and are not checked.
+func (p *Parser) checkArgs(call ast.BuiltinCall, fn object.Function, args []object.Object) {
+	params := fn.Params
+	if len(params) == 0 {
+		return
+	}
+
+	var (
+		pi          int              // index of the param the next argument is matched against
+		consumed    = map[int]bool{} // params matched by at least one argument
+		pairPending bool             // the last argument began a pair
+	)
+
+	for ai, arg := range call.Args {
+		clause := clauseOf(arg)
+
+		// skip forward to the first param that accepts the argument, reporting any
+		// required param on the way that has not been given. An argument that no
+		// param accepts is reported alone, and the params are left for the rest.
+		next := pi
+		for next < len(params) && params[next].Clause != clause {
+			next++
+		}
+		if next == len(params) {
+			p.unexpectedArg(arg, fn, pi)
+			continue
+		}
+		for ; pi < next; pi++ {
+			p.checkGiven(call, fn, arg, pi, consumed)
+		}
+
+		param := params[pi]
+		consumed[pi] = true
+		if !accepts(param, args[ai]) {
+			p.typeErr(arg, "%s: argument %s: have %s, want %s", fn.Name, param.Name, args[ai].Type(), typeList(param.Types))
+		}
+
+		pairPending = param.PairA
+		switch {
+		case clause == object.ClauseOver && pi+1 < len(params) && params[pi+1].Clause == object.ClauseAlias:
+			// the alias is part of the over clause, not an argument of its own.
+			if hasAlias(arg) {
+				consumed[pi+1] = true
+			}
+			pi++
+		case param.PairA:
+			// the second half of the pair always comes next.
+		case param.PairB && pi > 0 && (param.List || params[pi-1].List):
+			pi-- // back to the start of the pair for the next repetition.
+			continue
+		case param.List:
+			continue
+		}
+		pi++
+	}
+
+	// an unfinished pair is missing its second half.
+	if pairPending {
+		last := call.Args[len(call.Args)-1]
+		p.argErr(last, "%s: argument %s must be followed by %s", fn.Name, params[pi-1].Name, params[pi].Name)
+		pi++
+	}
+	for ; pi < len(params); pi++ {
+		p.checkGiven(call, fn, nil, pi, consumed)
+	}
+}
+
+// unexpectedArg reports arg, which no param from params[pi] on accepts.
+func (p *Parser) unexpectedArg(arg ast.Node, fn object.Function, pi int) {
+	if pi == len(fn.Params) {
+		p.argErr(arg, "%s: too many arguments, want %d", fn.Name, countRequired(fn.Params))
+		return
+	}
+	if param := fn.Params[pi]; param.Clause != "" {
+		p.argErr(arg, "%s: unexpected argument, want %s clause for argument %s", fn.Name, param.Clause, param.Name)
+		return
+	}
+	p.argErr(arg, "%s: unexpected argument, want argument %s", fn.Name, fn.Params[pi].Name)
+}
+
+// checkGiven reports fn.Params[pi] as missing if it is required and has not been
+// given. The error is reported against the argument that took its place, or the
+// end of call if arg is nil.
+func (p *Parser) checkGiven(call ast.BuiltinCall, fn object.Function, arg ast.Node, pi int, consumed map[int]bool) {
+	param := fn.Params[pi]
+	// by clauses are not yet supported by the parser, so cannot be required.
+	if param.Optional || consumed[pi] || param.Clause == object.ClauseBy {
+		return
+	}
+	if arg == nil {
+		arg = ast.BlankExpression{Token: call.Last}
+	}
+	if param.Clause != "" {
+		p.argErr(arg, "%s: missing %s clause for argument %s", fn.Name, param.Clause, param.Name)
+		return
+	}
+	p.argErr(arg, "%s: missing argument %s", fn.Name, param.Name)
+}
+
+func (p *Parser) argErr(n ast.Node, format string, a ...any) {
+	err := newTypeErr(n, format, a...)
+	err.ErrCode = CodeArgCount
+	p.typeErrors = append(p.typeErrors, err)
+}
+
+// clauseOf returns the clause keyword that introduces arg, if any.
+func clauseOf(arg ast.Expression) string {
+	if block, ok := arg.(ast.BlockExpression); ok && len(block.Vars) == 0 {
+		arg = block.Value
+	}
+	switch arg.(type) {
+	case ast.OverExpression:
+		return object.ClauseOver
+	case ast.WhereExpression:
+		return object.ClauseWhere
+	case ast.OrderByExpression:
+		return object.ClauseOrderBy
+	}
+	return ""
+}
+
+func hasAlias(arg ast.Expression) bool {
+	if block, ok := arg.(ast.BlockExpression); ok {
+		arg = block.Value
+	}
+	over, ok := arg.(ast.OverExpression)
+	return ok && over.HasAlias
+}
+
+// accepts returns true if an argument resolved to obj can be passed to param.
+// Arguments of unknown type are always accepted.
+func accepts(param object.Param, obj object.Object) bool {
+	t := obj.Type()
+	if !known(obj) || slices.Contains(param.Types, types.T_ANY) || slices.Contains(param.Types, t) {
+		return true
+	}
+	if t == types.T_NULL {
+		return slices.ContainsFunc(param.Types, types.Type.IsNullable)
+	}
+	return false
+}
+
+func countRequired(params []object.Param) int {
+	n := 0
+	for _, param := range params {
+		if !param.Optional && param.Clause != object.ClauseAlias && param.Clause != object.ClauseBy {
+			n++
+		}
+	}
+	return n
+}
+
+func typeList(ts []types.Type) string {
+	s := make([]string, len(ts))
+	for i, t := range ts {
+		s[i] = string(t)
+	}
+	return strings.Join(s, "|")
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/testhelp"
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func TestCheckArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErrs []string // codes, in order
+		wantCols []uint   // start column of each error
+	}{
+		{`contains("abc", "b")`, nil, nil},
+		{`contains("abc")`, []string{CodeArgCount}, []uint{14}},
+		{`contains("abc", "b", "c")`, []string{CodeArgCount}, []uint{21}},
+		{`contains("abc", 1)`, []string{CodeTypeMismatch}, []uint{16}},
+		{`contains(1, 2)`, []string{CodeTypeMismatch, CodeTypeMismatch}, []uint{9, 12}},
+		{`min(1, 2, 3, 4)`, nil, nil},
+		{`min(1, "a", 3)`, []string{CodeTypeMismatch}, []uint{7}},
+		{`min()`, []string{CodeArgCount}, []uint{4}},
+		{`if(true, 1)`, []string{CodeArgCount}, []uint{10}},
+		{`if(1, 1, 2)`, []string{CodeTypeMismatch}, []uint{3}},
+		{`if(x, "a", null_x)`, nil, nil},
+		{`count(over day alias d, where d.hours > 0)`, nil, nil},
+		{`count(over day)`, []string{CodeArgCount}, []uint{14}},
+		{`count(over "a", where true)`, []string{CodeTypeMismatch}, []uint{6}},
+		{`sum(over period alias p, 1, where true)`, nil, nil},
+		{`sum(over period, "a")`, []string{CodeTypeMismatch}, []uint{17}},
+		{`sum(1)`, []string{CodeArgCount}, []uint{4}},
+		{`findFirstTime(over period alias t, 1, where true, order by t.hours)`, []string{CodeArgCount}, []uint{35}},
+		{`count(over day, 1, where true)`, []string{CodeArgCount}, []uint{16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs, _ := testRunParser(t, tt.input, 1, false)
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+		})
+	}
+}
+
+func TestCheckArgsMessage(t *testing.T) {
+	prs, _ := testRunParser(t, `COUNT(over day, 1, where true)`, 1, false)
+	errs := prs.TypeErrors()
+	if len(errs) != 1 {
+		t.Fatalf("errors: have %v, want 1", errs)
+	}
+	if want := "count: unexpected argument, want where clause for argument condition"; errs[0].Error() != want {
+		t.Errorf("have %q, want %q", errs[0].Error(), want)
+	}
+}
+
+func TestCheckArgsPairs(t *testing.T) {
+	fn := object.Function{
+		Name: "pairs",
+		Params: []object.Param{
+			{Name: "key", Types: []types.Type{types.T_STRING}, PairA: true, List: true},
+			{Name: "value", Types: []types.Type{types.T_NUMBER}, PairB: true},
+			{Name: "default", Types: []types.Type{types.T_NUMBER}, Optional: true},
+		},
+	}
+
+	tests := []struct {
+		input    string
+		wantErrs []string
+		wantCols []uint
+	}{
+		{`min("a", 1)`, nil, nil},
+		{`min("a", 1, "b", 2, "c", 3)`, nil, nil},
+		{`min("a", 1, "b")`, []string{CodeArgCount}, []uint{12}},
+		{`min("a", "b")`, []string{CodeTypeMismatch}, []uint{9}},
+		{`min("a")`, []string{CodeArgCount}, []uint{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs, AST := testRunParser(t, tt.input, 1, false)
+			call := testhelp.AssertType[ast.BuiltinCall](t, testExpressionStatement(t, AST.Statements[0]))
+
+			args := make([]object.Object, len(call.Args))
+			for i, arg := range call.Args {
+				args[i], _ = prs.TypeOf(arg)
+			}
+			prs.typeErrors = []error{}
+			prs.checkArgs(call, fn, args)
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+		})
+	}
+}
+
+func testTypeErrs(t testhelp.TH, errs []error, wantCodes []string, wantCols []uint) {
+	if len(errs) != len(wantCodes) {
+		t.Fatalf("errors: have %d %v, want %d", len(errs), errs, len(wantCodes))
+	}
+	for i, err := range errs {
+		typeErr := testhelp.AssertType[TypeErr](t, err)
+		if typeErr.Code() != wantCodes[i] {
+			t.Errorf("error %d code: have %s, want %s (%s)", i, typeErr.Code(), wantCodes[i], err)
+		}
+		if start, _ := typeErr.Pos(); start != (token.Pos{Line: 0, Col: wantCols[i]}) {
+			t.Errorf("error %d start: have %s, want col %d (%s)", i, start, wantCols[i], err)
+		}
+	}
+}
+
+func TestBuiltinExamples(t *testing.T) {
+	for name, fn := range object.Builtins() {
+		for _, example := range fn.Examples {
+			t.Run(name, func(t *testing.T) {
+				prs, _ := testRunParser(t, example, 1, false)
+				if len(prs.TypeErrors()) > 0 {
+					t.Errorf("type errors in example %q: %v", example, prs.TypeErrors())
+				}
+			})
+		}
+	}
+}
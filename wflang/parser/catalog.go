@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// checkInList checks the list of an in expression against the catalog: the name
+// of a policy set, or the strings of a list compared with a pay code field.
+// Nothing is checked for the parts of the catalog that are empty.
+func (p *Parser) checkInList(in ast.InExpression) {
+	switch list := in.List.(type) {
+	case ast.SetExpression:
+		if !p.catalog.HasPolicySets() {
+			return
+		}
+		name := list.Name.Token.Literal
+		if _, ok := p.catalog.PolicySet(name); !ok {
+			msg := fmt.Sprintf("unknown policy set %s", name)
+			if suggestion, ok := p.catalog.SuggestPolicySet(name); ok {
+				msg += fmt.Sprintf(", did you mean %s?", suggestion)
+			}
+			p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: list.Name, ErrCode: CodeUnknownPolicySet})
+		}
+
+	case ast.ListLiteral:
+		if !p.catalog.HasPayCodes() || !isPayCodeField(in.Left) {
+			return
+		}
+		for _, str := range list.Strings {
+			code := strings.TrimSuffix(strings.TrimPrefix(str.Literal, `"`), `"`)
+			if _, ok := p.catalog.PayCode(code); !ok {
+				msg := fmt.Sprintf("unknown pay code %q", code)
+				if suggestion, ok := p.catalog.SuggestPayCode(code); ok {
+					msg += fmt.Sprintf(", did you mean %q?", suggestion)
+				}
+				p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: str, ErrCode: CodeUnknownPayCode})
+			}
+		}
+	}
+}
+
+// isPayCodeField returns true if n is the pay code field of a record.
+func isPayCodeField(n ast.Expression) bool {
+	member, ok := n.(ast.MemberExpression)
+	return ok && strings.EqualFold(member.Member.Token.Literal, object.FieldPayCode)
+}
+
+// AttributeArg returns the employee attribute argument of call, if call takes one:
+// an ident, or for getAttributeCalculationDate a string.
+func AttributeArg(call ast.BuiltinCall) (name string, arg ast.Expression, ok bool) {
+	if len(call.Args) == 0 {
+		return "", nil, false
+	}
+	block, isBlock := call.Args[0].(ast.BlockExpression)
+	if !isBlock || len(block.Vars) > 0 {
+		return "", nil, false
+	}
+	switch strings.ToLower(call.Name) {
+	case object.EmployeeAttribute, object.EmployeeAttributeExists:
+		if id, isIdent := block.Value.(ast.Ident); isIdent {
+			return id.Token.Literal, id, true
+		}
+	case object.GetAttributeCalcDate:
+		if str, isStr := block.Value.(ast.StringLiteral); isStr {
+			return strings.TrimSuffix(strings.TrimPrefix(str.Literal, `"`), `"`), str, true
+		}
+	}
+	return "", nil, false
+}
+
+// checkAttribute checks the employee attribute argument of call against the
+// catalog, and returns the attribute if it is known.
+func (p *Parser) checkAttribute(call ast.BuiltinCall) (catalog.Attribute, bool) {
+	name, arg, ok := AttributeArg(call)
+	if !ok || !p.catalog.HasAttributes() {
+		return catalog.Attribute{}, false
+	}
+	attr, ok := p.catalog.Attribute(name)
+	if !ok {
+		msg := fmt.Sprintf("unknown employee attribute %s", name)
+		if suggestion, ok := p.catalog.SuggestAttribute(name); ok {
+			msg += fmt.Sprintf(", did you mean %s?", suggestion)
+		}
+		p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: arg, ErrCode: CodeUnknownAttribute})
+	}
+	return attr, ok
+}
+
+// checkMacro checks the name of macro against the catalog, and returns the object
+// of the value it expands to: that of its type if it is known, or Any, as the
+// content of a macro is unknown to the formula.
+func (p *Parser) checkMacro(macro ast.MacroExpression) object.Object {
+	if !p.catalog.HasMacros() {
+		return object.Any{}
+	}
+	name := macro.Name.Token.Literal
+	m, ok := p.catalog.Macro(name)
+	if !ok {
+		msg := fmt.Sprintf("unknown macro %s", name)
+		if suggestion, ok := p.catalog.SuggestMacro(name); ok {
+			msg += fmt.Sprintf(", did you mean %s?", suggestion)
+		}
+		p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: macro.Name, ErrCode: CodeUnknownMacro})
+		return object.Any{}
+	}
+	return object.FromType(m.Type)
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/catalog"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+func testCatalog() *catalog.Catalog {
+	cat := &catalog.Catalog{}
+	cat.AddPolicySet(catalog.PolicySet{Name: "OT_CODES", Members: []string{"OT", "DT"}})
+	cat.AddPayCode(catalog.PayCode{Code: "REG"})
+	cat.AddPayCode(catalog.PayCode{Code: "OT"})
+	return cat
+}
+
+func TestCheckInList(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErrs []string
+		wantCols []uint
+		wantMsgs []string
+	}{
+		{`count(over day alias d, where d.pay_code in set ot_codes)`, nil, nil, nil},
+		{`count(over day alias d, where d.pay_code in set OT_CODE)`, []string{CodeUnknownPolicySet}, []uint{48}, []string{"unknown policy set OT_CODE, did you mean OT_CODES?"}},
+		{`count(over day alias d, where d.pay_code in set HOLIDAY)`, []string{CodeUnknownPolicySet}, []uint{48}, []string{"unknown policy set HOLIDAY"}},
+		{`count(over day alias d, where d.pay_code in ["REG", "OT"])`, nil, nil, nil},
+		{`count(over day alias d, where d.pay_code in ["RGE", "ot", "SICK"])`, []string{CodeUnknownPayCode, CodeUnknownPayCode, CodeUnknownPayCode}, []uint{45, 52, 58}, []string{`unknown pay code "RGE", did you mean "REG"?`, `unknown pay code "ot", did you mean "OT"?`, `unknown pay code "SICK"`}},
+		{`count(over day alias d, where d.comment in ["SICK"])`, nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs := New(lexer.New(tt.input), WithCatalog(testCatalog()))
+			if len(prs.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", prs.Errors())
+			}
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+			for i, err := range prs.TypeErrors() {
+				if err.Error() != tt.wantMsgs[i] {
+					t.Errorf("error %d: have %q, want %q", i, err, tt.wantMsgs[i])
+				}
+			}
+		})
+	}
+
+	prs := New(lexer.New(`count(over day alias d, where d.pay_code in set HOLIDAY)`))
+	if len(prs.TypeErrors()) > 0 {
+		t.Errorf("have %v, want nothing checked without a catalog", prs.TypeErrors())
+	}
+}
+
+func TestCheckAttribute(t *testing.T) {
+	cat := &catalog.Catalog{}
+	cat.AddAttribute(catalog.Attribute{Name: "HOURLY_RATE", Type: types.T_NUMBER})
+	cat.AddAttribute(catalog.Attribute{Name: "UNION", Type: types.T_STRING})
+
+	tests := []struct {
+		input    string
+		wantErrs []string
+		wantCols []uint
+		wantType types.Type // of the statement.
+	}{
+		{`employee_attribute(HOURLY_RATE, day) * 1.5`, nil, nil, types.T_NUMBER},
+		{`employee_attribute(hourly_rate) * 1.5`, nil, nil, types.T_NUMBER},
+		{`employee_attribute(UNION) * 1.5`, []string{CodeTypeMismatch}, []uint{0}, types.T_UNDEFINED},
+		{`employee_attribute(HOURLY_RAT, day)`, []string{CodeUnknownAttribute}, []uint{19}, types.T_ANY},
+		{`employee_attribute_exists(UNOIN, day)`, []string{CodeUnknownAttribute}, []uint{26}, types.T_BOOL},
+		{`getAttributeCalculationDate("UNION", day)`, nil, nil, types.T_DATE},
+		{`getAttributeCalculationDate("PENSION", day)`, []string{CodeUnknownAttribute}, []uint{28}, types.T_DATE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs := New(lexer.New(tt.input), WithCatalog(cat))
+			if len(prs.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", prs.Errors())
+			}
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+			stmt := prs.Statements()[0].(ast.ExpressionStatement)
+			if obj, ok := prs.TypeOf(stmt.Expression); !ok || obj.Type() != tt.wantType {
+				t.Errorf("have type %v, want %s", obj, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestCheckMacro(t *testing.T) {
+	cat := &catalog.Catalog{}
+	cat.AddMacro(catalog.Macro{Name: "GO_LIVE_DATE", Type: types.T_DATE})
+	cat.AddMacro(catalog.Macro{Name: "BASE_HOURS", Type: types.T_NUMBER})
+	cat.AddMacro(catalog.Macro{Name: "RULE", Type: types.T_ANY})
+
+	tests := []struct {
+		input    string
+		wantErrs []string
+		wantCols []uint
+		wantType types.Type // of the statement.
+	}{
+		{`$BASE_HOURS()$ * 1.5`, nil, nil, types.T_NUMBER},
+		{`$go_live_date()$`, nil, nil, types.T_DATE},
+		{`$RULE(1, "x")$`, nil, nil, types.T_ANY},
+		{`$GO_LIVE_DATE()$ * 1.5`, []string{CodeTypeMismatch}, []uint{1}, types.T_UNDEFINED},
+		{`$BASE_HOUR()$`, []string{CodeUnknownMacro}, []uint{1}, types.T_ANY},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs := New(lexer.New(tt.input), WithCatalog(cat))
+			if len(prs.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", prs.Errors())
+			}
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+			stmt := prs.Statements()[0].(ast.ExpressionStatement)
+			if obj, ok := prs.TypeOf(stmt.Expression); !ok || obj.Type() != tt.wantType {
+				t.Errorf("have type %v, want %s", obj, tt.wantType)
+			}
+		})
+	}
+
+	prs := New(lexer.New(`$ANYTHING()$`))
+	if len(prs.TypeErrors()) > 0 {
+		t.Errorf("have %v, want nothing checked without macros", prs.TypeErrors())
+	}
+}
@@ -1,29 +1,74 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
 	"github.com/scatternoodle/wflang/wflang/token"
 )
 
 func newParseErr(msg string, tok token.Token) ParseErr {
-	return ParseErr{msg, tok}
+	return ParseErr{Msg: msg, Token: tok}
+}
+
+// ParseErr is an alias of ast.ParseErr, which lives in the ast package so that
+// it can be walked in place of a valid node.
+type ParseErr = ast.ParseErr
+
+// Stable diagnostic codes of TypeErr.
+const (
+	CodeTypeMismatch     string = "type-mismatch"
+	CodeArgCount         string = "arg-count"
+	CodeUnknownPolicySet string = "unknown-policy-set"
+	CodeUnknownPayCode   string = "unknown-pay-code"
+	CodeUnknownAttribute string = "unknown-attribute"
+	CodeUnknownField     string = "unknown-field"
+	CodeUnknownMacro     string = "unknown-macro"
+	CodeUnknownMethod    string = "unknown-method"
+)
+
+// Codes returns the stable diagnostic codes of the errors the parser reports: that
+// of ParseErr, and the Code consts of TypeErr.
+func Codes() []string {
+	return []string{
+		ParseErr{}.Code(), CodeTypeMismatch, CodeArgCount, CodeUnknownPolicySet, CodeUnknownPayCode,
+		CodeUnknownAttribute, CodeUnknownField, CodeUnknownMacro, CodeUnknownMethod,
+	}
 }
 
-// ParseErr is a struct that represents an error that occurred during the parsing
-// process. It implements both expression and statement interfaces in addition to the
-// error interface, so that it can also traverse the AST in place of a valid node.
-type ParseErr struct {
-	Msg   string
-	Token token.Token
+// TypeErr is a semantic error found while resolving the types of an otherwise
+// valid AST, such as an operator applied to operands of the wrong type.
+type TypeErr struct {
+	Msg     string
+	Node    ast.Node // the node at which the error was detected.
+	ErrCode string   // one of the Code consts, defaults to CodeTypeMismatch.
 }
 
-func (p ParseErr) Error() string {
-	return p.Msg
+func newTypeErr(node ast.Node, format string, a ...any) TypeErr {
+	return TypeErr{Msg: fmt.Sprintf(format, a...), Node: node}
 }
 
-func (p ParseErr) String() string {
-	return p.Error()
+func (t TypeErr) Error() string { return t.Msg }
+
+// Code returns the stable diagnostic code of the error.
+func (t TypeErr) Code() string {
+	if t.ErrCode == "" {
+		return CodeTypeMismatch
+	}
+	return t.ErrCode
 }
 
-func (p ParseErr) TokenLiteral() string {
-	return p.Token.Literal
+// Pos returns the StartPos and EndPos of the node at which the error was detected.
+func (t TypeErr) Pos() (start, end token.Pos) { return t.Node.Pos() }
+
+// Message returns the message of err for display. Parse errors are returned wrapped
+// in the context of each node that failed to parse, so their message is that of
+// the innermost ParseErr.
+func Message(err error) string {
+	var pErr ParseErr
+	if errors.As(err, &pErr) {
+		return pErr.Msg
+	}
+	return err.Error()
 }
@@ -1,40 +1,416 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/catalog"
 	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
 )
 
+// nodeKey identifies a node in the typed side table. Nodes are values, some of
+// which are not comparable, so they are keyed by position and concrete type.
+type nodeKey struct {
+	start, end token.Pos
+	nodeType   string
+}
+
+func keyOf(n ast.Node) nodeKey {
+	start, end := n.Pos()
+	return nodeKey{start: start, end: end, nodeType: fmt.Sprintf("%T", n)}
+}
+
+// TypeOf returns the object resolved for node n during evaluation. Returns false
+// if n was not evaluated.
+func (p *Parser) TypeOf(n ast.Node) (object.Object, bool) {
+	obj, ok := p.types[keyOf(n)]
+	return obj, ok
+}
+
+// TypeErrors returns the semantic errors found during evaluation. These are kept
+// apart from Errors, as the AST is syntactically valid regardless.
+func (p *Parser) TypeErrors() []error { return p.typeErrors }
+
+// globals returns the summary contexts that are always in scope.
+func globals() map[string]object.Object {
+	return map[string]object.Object{
+		"day":    object.Day{},
+		"week":   object.Week{},
+		"period": object.Period{},
+	}
+}
+
 // eval descends an AST recursively and evaluates the statements, resolving
 // object types and looking for more advanced syntax and semantic errors than what
-// the lexer / parser can detect.
+// the lexer / parser can detect. The resolved object of every node is recorded,
+// and can be queried with TypeOf.
 func (p *Parser) eval(n ast.Node) object.Object {
+	if n == nil {
+		return object.Undefined{}
+	}
 	var obj object.Object
 	switch v := n.(type) {
 	case *ast.AST:
+		p.types = map[nodeKey]object.Object{}
+		p.typeErrors = []error{}
+		// names are looked up by the declarations they resolved to.
+		p.decls = map[token.Pos]*resolve.Decl{}
+		p.declared = map[*resolve.Decl]object.Object{}
+		for _, decl := range p.resolved.Decls {
+			p.decls[decl.Name.StartPos] = decl
+		}
+		for _, ref := range p.resolved.Refs {
+			p.decls[ref.Ident.StartPos] = ref.Decl
+		}
 		for _, stmt := range v.Statements {
 			obj = p.eval(stmt)
 		}
+		return obj // the AST itself is not recorded.
 
 	case ast.ExpressionStatement:
 		obj = p.eval(v.Expression)
 
 	case ast.VarStatement:
-		obj = object.Variable{
+		variable := object.Variable{
 			Name:      v.Name.Value,
 			Statement: &v,
 			Val:       p.eval(v.Value),
 		}
-		p.vars = append(p.vars, obj.(object.Variable))
+		p.declare(v.Name, variable.Val)
+		obj = variable
+
+	case ast.Ident:
+		obj = p.lookup(v)
 
 	case ast.NumberLiteral:
 		obj = object.Number{Val: v.Val, Static: true}
 	case ast.StringLiteral:
 		obj = object.String{Val: v.Literal, Static: true}
+	case ast.BooleanLiteral:
+		obj = object.Boolean{Val: v.Value, Static: true}
+	case ast.NullLiteral:
+		obj = object.Null{}
+	case ast.DateLiteral:
+		obj = object.Date{Val: v.Time, Static: true}
+	case ast.TimeLiteral:
+		obj = object.Time{Val: v.Time, Static: true}
+
+	case ast.PrefixExpression:
+		obj = p.evalPrefix(v)
+	case ast.InfixExpression:
+		obj = p.evalInfix(v)
+
+	case ast.InExpression:
+		left := p.eval(v.Left)
+		p.eval(v.List)
+		if known(left) && left.Type() != types.T_STRING {
+			p.typeErr(v, "in: have %s, want %s", left.Type(), types.T_STRING)
+		}
+		p.checkInList(v)
+		obj = object.Boolean{}
+
+	case ast.ParenExpression:
+		obj = p.eval(v.Inner)
+
+	case ast.BlockExpression:
+		for _, vs := range v.Vars {
+			p.eval(vs)
+		}
+		obj = p.eval(v.Value)
+
+	case ast.BuiltinCall:
+		obj = p.evalBuiltinCall(v)
+
+	case ast.MacroExpression:
+		for _, arg := range v.Args {
+			p.eval(arg)
+		}
+		obj = p.checkMacro(v)
+
+	case ast.MemberExpression:
+		obj = p.evalMember(v)
+
+	case ast.OverExpression:
+		obj = p.eval(v.Context)
+		if v.HasAlias {
+			p.declare(v.Alias.Alias, p.aliasObject())
+			p.eval(v.Alias)
+		}
+
+	case ast.AliasExpression:
+		obj = p.lookup(v.Alias)
+
+	case ast.WhereExpression:
+		cond := p.eval(v.Condition)
+		if known(cond) && cond.Type() != types.T_BOOL {
+			p.typeErr(v.Condition, "where condition: have %s, want %s", cond.Type(), types.T_BOOL)
+		}
+		obj = object.Boolean{}
+
+	case ast.OrderByExpression:
+		p.eval(v.Expression)
+		obj = object.Any{}
 
 	default:
 		obj = object.Undefined{Val: v}
 	}
 
+	p.types[keyOf(n)] = obj
 	return obj
 }
+
+func (p *Parser) evalPrefix(v ast.PrefixExpression) object.Object {
+	right := p.eval(v.Right)
+	var want types.Type
+	switch v.Token.Type {
+	case token.T_MINUS:
+		want = types.T_NUMBER
+	case token.T_BANG:
+		want = types.T_BOOL
+	default:
+		return object.Undefined{Val: v}
+	}
+	if known(right) && right.Type() != want {
+		p.typeErr(v, "operator %s: have %s, want %s", v.Prefix, right.Type(), want)
+	}
+	return object.FromType(want)
+}
+
+func (p *Parser) evalInfix(v ast.InfixExpression) object.Object {
+	left, right := p.eval(v.Left), p.eval(v.Right)
+	lt, rt := left.Type(), right.Type()
+	mismatch := func() object.Object {
+		p.typeErr(v, "operator %s: mismatched types %s and %s", v.Infix, lt, rt)
+		return object.Undefined{Val: v}
+	}
+
+	switch v.Token.Type {
+	case token.T_AND, token.T_OR:
+		for _, operand := range []object.Object{left, right} {
+			if known(operand) && operand.Type() != types.T_BOOL {
+				p.typeErr(v, "operator %s: have %s, want %s", v.Infix, operand.Type(), types.T_BOOL)
+				break
+			}
+		}
+		return object.Boolean{}
+
+	case token.T_EQ, token.T_NEQ:
+		if known(left) && known(right) && lt != rt && lt != types.T_NULL && rt != types.T_NULL {
+			mismatch()
+		}
+		return object.Boolean{}
+
+	case token.T_LT, token.T_GT, token.T_LTE, token.T_GTE:
+		if !known(left) || !known(right) {
+			return object.Boolean{}
+		}
+		if lt != rt {
+			mismatch()
+		} else if !isOrdered(lt) {
+			p.typeErr(v, "operator %s: type %s is not ordered", v.Infix, lt)
+		}
+		return object.Boolean{}
+
+	case token.T_PLUS, token.T_MINUS, token.T_ASTERISK, token.T_SLASH, token.T_MODULO:
+		if !known(left) || !known(right) {
+			if known(left) && lt == types.T_NUMBER || known(right) && rt == types.T_NUMBER {
+				return object.Number{}
+			}
+			return object.Any{}
+		}
+		if t, ok := arithmeticType(v.Token.Type, lt, rt); ok {
+			return object.FromType(t)
+		}
+		return mismatch()
+	}
+	return object.Undefined{Val: v}
+}
+
+// arithmeticType returns the result type of the arithmetic operator op applied to
+// operands of types l and r, or false if the operator is not defined for them.
+func arithmeticType(op token.Type, l, r types.Type) (types.Type, bool) {
+	if l == types.T_NUMBER && r == types.T_NUMBER {
+		return types.T_NUMBER, true
+	}
+	switch op {
+	case token.T_PLUS:
+		if l == types.T_STRING && r == types.T_STRING {
+			return types.T_STRING, true
+		}
+		if isTemporal(l) && r == types.T_NUMBER {
+			return l, true
+		}
+		if l == types.T_NUMBER && isTemporal(r) {
+			return r, true
+		}
+	case token.T_MINUS:
+		if isTemporal(l) && r == types.T_NUMBER {
+			return l, true
+		}
+		if isTemporal(l) && l == r {
+			return types.T_NUMBER, true
+		}
+	}
+	return "", false
+}
+
+func isTemporal(t types.Type) bool {
+	return t == types.T_DATE || t == types.T_TIME || t == types.T_DTTM
+}
+
+func isOrdered(t types.Type) bool {
+	return t == types.T_NUMBER || t == types.T_STRING || isTemporal(t)
+}
+
+// known returns true if the type of obj has been resolved to a concrete type.
+func known(obj object.Object) bool {
+	t := obj.Type()
+	return t != types.T_ANY && t != types.T_UNDEFINED
+}
+
+func (p *Parser) evalBuiltinCall(v ast.BuiltinCall) object.Object {
+	fn, ok := object.Builtin(v.Name)
+	outer := p.records
+	p.records = fn.Records
+	args := make([]object.Object, len(v.Args))
+	for i, arg := range v.Args {
+		args[i] = p.eval(arg)
+	}
+	p.records = outer
+
+	if !ok {
+		return object.Undefined{Val: v}
+	}
+	p.checkArgs(v, fn, args)
+	if strings.ToLower(v.Name) == object.If && len(args) == 3 {
+		return p.unifyBranches(v, args[1], args[2])
+	}
+	if attr, ok := p.checkAttribute(v); ok && strings.ToLower(v.Name) == object.EmployeeAttribute {
+		return object.FromType(attr.Type)
+	}
+	return object.FromType(fn.ReturnType)
+}
+
+// aliasObject returns the object bound to the alias of the summary function being
+// evaluated: its record, or Any if it iterates something else.
+func (p *Parser) aliasObject() object.Object {
+	if p.records == "" {
+		return object.Any{}
+	}
+	return object.FromType(p.records)
+}
+
+// evalMember resolves the type of a field of a record against its schema, or of a
+// method of a record against RecordMethods. Members of anything else are not
+// known, and resolve to Any.
+func (p *Parser) evalMember(v ast.MemberExpression) object.Object {
+	record := p.eval(v.Object).Type()
+	args := make([]object.Object, len(v.Args))
+	for i, arg := range v.Args {
+		args[i] = p.eval(arg)
+	}
+	if _, ok := p.schemas[record]; !ok {
+		return object.Any{}
+	}
+	if v.Call {
+		return p.evalMethod(v, record, args)
+	}
+	name := v.Member.Token.Literal
+	if field, ok := p.schemas.Field(record, name); ok {
+		return object.FromType(field.Type)
+	}
+
+	var names []string
+	for _, field := range p.schemas[record] {
+		names = append(names, field.Name)
+	}
+	msg := fmt.Sprintf("unknown field %s of %s", name, record)
+	if method, ok := object.RecordMethod(record, name); ok {
+		msg = fmt.Sprintf("%s is a method of %s, call it as %s()", name, record, method.Name)
+	} else if suggestion, ok := catalog.Suggest(name, names); ok {
+		msg += fmt.Sprintf(", did you mean %s?", suggestion)
+	}
+	p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: v.Member, ErrCode: CodeUnknownField})
+	return object.Any{}
+}
+
+// evalMethod resolves the type of a call of a method of record, checking args
+// against its params.
+func (p *Parser) evalMethod(v ast.MemberExpression, record types.Type, args []object.Object) object.Object {
+	name := v.Member.Token.Literal
+	fn, ok := object.RecordMethod(record, name)
+	if !ok {
+		var names []string
+		for _, method := range object.RecordMethods()[record] {
+			names = append(names, method.Name)
+		}
+		msg := fmt.Sprintf("unknown method %s of %s", name, record)
+		if suggestion, ok := catalog.Suggest(name, names); ok {
+			msg += fmt.Sprintf(", did you mean %s?", suggestion)
+		}
+		p.typeErrors = append(p.typeErrors, TypeErr{Msg: msg, Node: v.Member, ErrCode: CodeUnknownMethod})
+		return object.Any{}
+	}
+
+	for i, arg := range args {
+		if i == len(fn.Params) {
+			p.argErr(v.Args[i], "%s: too many arguments, want %d", fn.Name, countRequired(fn.Params))
+			break
+		}
+		if param := fn.Params[i]; !accepts(param, arg) {
+			p.typeErr(v.Args[i], "%s: argument %s: have %s, want %s", fn.Name, param.Name, arg.Type(), typeList(param.Types))
+		}
+	}
+	for _, param := range fn.Params[min(len(args), len(fn.Params)):] {
+		if !param.Optional {
+			p.argErr(ast.BlankExpression{Token: v.RPar}, "%s: missing argument %s", fn.Name, param.Name)
+		}
+	}
+	return object.FromType(fn.ReturnType)
+}
+
+// unifyBranches returns the type shared by the then and else branches of an if
+// call. A null branch takes the type of the other.
+func (p *Parser) unifyBranches(call ast.BuiltinCall, then, els object.Object) object.Object {
+	switch {
+	case !known(then):
+		return els
+	case !known(els), then.Type() == els.Type(), els.Type() == types.T_NULL:
+		return object.FromType(then.Type())
+	case then.Type() == types.T_NULL:
+		return object.FromType(els.Type())
+	}
+	p.typeErr(call.Args[2], "if: branches have mismatched types %s and %s", then.Type(), els.Type())
+	return object.Any{}
+}
+
+// lookup returns the object of the declaration that id refers to, as resolved, or
+// of the global that it names. Returns Undefined if it is neither, or if it refers
+// to a var that is used before its value is evaluated.
+func (p *Parser) lookup(id ast.Ident) object.Object {
+	if decl, ok := p.decls[id.StartPos]; ok {
+		if obj, ok := p.declared[decl]; ok {
+			return obj
+		}
+		return object.Undefined{}
+	}
+	if obj, ok := globals()[strings.ToLower(id.Value)]; ok {
+		return obj
+	}
+	return object.Undefined{}
+}
+
+// declare records obj as the object of the var or alias declared by id.
+func (p *Parser) declare(id ast.Ident, obj object.Object) {
+	if decl, ok := p.decls[id.StartPos]; ok {
+		p.declared[decl] = obj
+	}
+}
+
+func (p *Parser) typeErr(n ast.Node, format string, a ...any) {
+	p.typeErrors = append(p.typeErrors, newTypeErr(n, format, a...))
+}
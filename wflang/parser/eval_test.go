@@ -3,9 +3,12 @@ package parser
 import (
 	"testing"
 
+	"github.com/scatternoodle/wflang/testhelp"
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/lexer"
 	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
 	"github.com/scatternoodle/wflang/wflang/types"
-	"github.com/scatternoodle/wflang/testhelp"
 )
 
 func TestEvalLiterals(t *testing.T) {
@@ -64,3 +67,138 @@ func testObjectVal(t testhelp.TH, obj object.Object, want any) bool {
 
 	return false
 }
+
+func TestEvalTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		want     types.Type
+		wantErrs int
+	}{
+		{`true`, types.T_BOOL, 0},
+		{`{2024-01-31}`, types.T_DATE, 0},
+		{`{09:30}`, types.T_TIME, 0},
+		{`1 + 2 * 3`, types.T_NUMBER, 0},
+		{`"a" + "b"`, types.T_STRING, 0},
+		{`"abc" + 1`, types.T_UNDEFINED, 1},
+		{`-"abc"`, types.T_NUMBER, 1},
+		{`!(1 > 2)`, types.T_BOOL, 0},
+		{`1 = 1 && "a" != "b"`, types.T_BOOL, 0},
+		{`1 && true`, types.T_BOOL, 1},
+		{`{2024-01-31} > 1`, types.T_BOOL, 1},
+		{`{2024-01-31} = 1`, types.T_BOOL, 1},
+		{`{2024-01-31} + 1`, types.T_DATE, 0},
+		{`{2024-01-31} - {2024-01-01}`, types.T_NUMBER, 0},
+		{`true < false`, types.T_BOOL, 1},
+		{`x + 1`, types.T_NUMBER, 0},
+		{`var x = "a"; x + 1`, types.T_UNDEFINED, 1},
+		{`var x = {2024-01-31}; x`, types.T_DATE, 0},
+		{`"REG" in ["REG", "OT"]`, types.T_BOOL, 0},
+		{`1 in set FOO`, types.T_BOOL, 1},
+		{`if(true, 1, 2)`, types.T_NUMBER, 0},
+		{`if(true, "a", 2)`, types.T_ANY, 1},
+		{`min(1, 2)`, types.T_NUMBER, 0},
+		{`makeDate(2024, 1, 1)`, types.T_DATE, 0},
+		{`toUpperCase("a") + 1`, types.T_UNDEFINED, 1},
+		{`if(true, (var y = 1; y), 2) + y`, types.T_NUMBER, 0},
+		{`var x = "a"; (var x = 1; x) + 1`, types.T_NUMBER, 0},
+		{`var x = "a"; (var x = 1; x) + x`, types.T_UNDEFINED, 1},
+		{`var a = b; var b = 1; a`, types.T_UNDEFINED, 0},
+		{`var t = "a"; sumTime(over day alias t, t.HOURS) + 1`, types.T_NUMBER, 0},
+		{`var day = "a"; day`, types.T_STRING, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			obj := testRunEval(t, tt.input, -1, false)
+			if obj.Type() != tt.want {
+				t.Errorf("type: have %s, want %s", obj.Type(), tt.want)
+			}
+		})
+		t.Run(tt.input+" errors", func(t *testing.T) {
+			prs, _ := testRunParser(t, tt.input, -1, false)
+			if have := len(prs.TypeErrors()); have != tt.wantErrs {
+				t.Errorf("type errors: have %d %v, want %d", have, prs.TypeErrors(), tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	prs, AST := testRunParser(t, `var x = "abc" + 1;`, 1, false)
+
+	vs := testhelp.AssertType[ast.VarStatement](t, AST.Statements[0])
+	infix := testhelp.AssertType[ast.InfixExpression](t, vs.Value)
+	for _, n := range []ast.Node{infix.Left, infix.Right} {
+		if _, ok := prs.TypeOf(n); !ok {
+			t.Errorf("no type recorded for %s", n)
+		}
+	}
+	if obj, _ := prs.TypeOf(infix.Left); obj.Type() != types.T_STRING {
+		t.Errorf("left: have %s, want %s", obj.Type(), types.T_STRING)
+	}
+
+	errs := prs.TypeErrors()
+	if len(errs) != 1 {
+		t.Fatalf("type errors: have %d, want 1", len(errs))
+	}
+	typeErr := testhelp.AssertType[TypeErr](t, errs[0])
+	start, end := typeErr.Pos()
+	if want := (token.Pos{Line: 0, Col: 8}); start != want {
+		t.Errorf("start: have %s, want %s", start, want)
+	}
+	if want := (token.Pos{Line: 0, Col: 16}); end != want {
+		t.Errorf("end: have %s, want %s", end, want)
+	}
+}
+
+func TestEvalMember(t *testing.T) {
+	schemas := object.DefaultSchemas().Extend(object.Schemas{
+		types.T_TIMEREC: {{Name: "DEPARTMENT", Type: types.T_STRING}},
+	})
+
+	tests := []struct {
+		input    string
+		wantErrs []string
+		wantCols []uint
+		wantMsgs []string
+	}{
+		{`sumTime(over day alias t, t.HOURS, where t.pay_code = "REG")`, nil, nil, nil},
+		{`sumTime(over day alias t, t.HOUR)`, []string{CodeUnknownField}, []uint{28}, []string{"unknown field HOUR of timeRecord, did you mean HOURS?"}},
+		{`sumTime(over day alias t, t.hour)`, []string{CodeUnknownField}, []uint{28}, []string{"unknown field hour of timeRecord, did you mean HOURS?"}},
+		{`sumTime(over day alias t, t.PAY_CODE)`, []string{CodeTypeMismatch}, []uint{26}, nil},
+		{`countSchedule(over day alias s, where s.START_DTTM < s.END_DTTM)`, nil, nil, nil},
+		{`countTime(over day alias t, where t.start_dttm < t.end_dttm and t.work_rule = "A")`, nil, nil, nil},
+		{`countTime(over day alias t, where t.work_rule = 1)`, []string{CodeTypeMismatch}, []uint{34}, nil},
+		{`countException(over day alias e, where e.HOURS > 1)`, []string{CodeUnknownField}, []uint{41}, []string{"unknown field HOURS of exception"}},
+		{`countTime(over day alias t, where t.department = "A")`, nil, nil, nil},
+		{`countTime(over day alias t, where t.department = 1)`, []string{CodeTypeMismatch}, []uint{34}, nil},
+		{`sumTime(over day alias t, countException(over day alias e, where e.SEVERITY = t.PAY_CODE))`, nil, nil, nil},
+		{`count(over day alias d, where d.anything > 0)`, nil, nil, nil},
+		{`countTime(over day alias t, where t.hasTimes() and t.timeRange() != null)`, nil, nil, nil},
+		{`countTime(over day alias t, where t.HASTIMES())`, nil, nil, nil},
+		{`countTime(over day alias t, where t.hasTimes() > 1)`, []string{CodeTypeMismatch}, []uint{34}, nil},
+		{`countException(over day alias e, where e.isSeverity("HIGH"))`, nil, nil, nil},
+		{`countException(over day alias e, where e.isSeverity(1))`, []string{CodeTypeMismatch}, []uint{52}, []string{"isSeverity: argument severity: have number, want string"}},
+		{`countException(over day alias e, where e.isSeverity())`, []string{CodeArgCount}, []uint{52}, []string{"isSeverity: missing argument severity"}},
+		{`countTime(over day alias t, where t.hasTimes(1))`, []string{CodeArgCount}, []uint{45}, []string{"hasTimes: too many arguments, want 0"}},
+		{`countTime(over day alias t, where t.hasTime())`, []string{CodeUnknownMethod}, []uint{36}, []string{"unknown method hasTime of timeRecord, did you mean hasTimes?"}},
+		{`countTime(over day alias t, where t.HOURS())`, []string{CodeUnknownMethod}, []uint{36}, []string{"unknown method HOURS of timeRecord"}},
+		{`countTime(over day alias t, where t.hasTimes)`, []string{CodeUnknownField}, []uint{36}, []string{"hasTimes is a method of timeRecord, call it as hasTimes()"}},
+		{`count(over day alias d, where d.anything(1 + "a"))`, []string{CodeTypeMismatch}, []uint{41}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prs := New(lexer.New(tt.input), WithSchemas(schemas))
+			if len(prs.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", prs.Errors())
+			}
+			testTypeErrs(t, prs.TypeErrors(), tt.wantErrs, tt.wantCols)
+			for i, msg := range tt.wantMsgs {
+				if err := prs.TypeErrors()[i]; err.Error() != msg {
+					t.Errorf("error %d: have %q, want %q", i, err, msg)
+				}
+			}
+		})
+	}
+}
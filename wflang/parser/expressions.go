@@ -16,7 +16,11 @@ func blankExpression(t token.Token) ast.Expression {
 
 // parseExpression, similarly to parseStatement, is mainly a triage function that
 // delegates to the appropriate parsing function based on the current token type.
-func (p *Parser) parseExpression() (ast.Expression, error) {
+//
+// Infix operators are folded into the left expression for as long as they bind
+// tighter than precedence, which gives us Pratt-style operator precedence and
+// left associativity for operators of equal precedence.
+func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 	prefix, ok := p.prefixParsers[p.current.Type]
 	if !ok {
 		errMsg := fmt.Sprintf("no prefix parser mapped for token type %s", p.current.Type)
@@ -28,7 +32,7 @@ func (p *Parser) parseExpression() (ast.Expression, error) {
 		return nil, fmt.Errorf("error parsing prefix: %w", err)
 	}
 
-	for p.next.Type != token.T_SEMICOLON && p.next.Type != token.T_EOF /*&& precedence < p.peekPrecedence()*/ {
+	for p.next.Type != token.T_SEMICOLON && p.next.Type != token.T_EOF && precedence < p.peekPrecedence() {
 		infix, ok := p.infixParsers[p.next.Type]
 		if !ok {
 			return leftExp, nil
@@ -53,7 +57,7 @@ func (p *Parser) parsePrefixExpression() (ast.Expression, error) {
 	}
 	p.advance()
 
-	right, err := p.parseExpression()
+	right, err := p.parseExpression(precPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing right expression: %w", err)
 	}
@@ -74,9 +78,10 @@ func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, erro
 		Left:  left,
 		Infix: p.current.Literal,
 	}
+	precedence := p.currentPrecedence()
 	p.advance()
 
-	right, err := p.parseExpression()
+	right, err := p.parseExpression(precedence)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing right expresion: %w", err)
 	}
@@ -84,6 +89,44 @@ func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, erro
 	return exp, nil
 }
 
+// parseMemberExpression - looks like:
+//
+//	object<Expression>.<Ident>
+//	object<Expression>.<Ident>([]<Expression>)
+//
+// Members live in the namespace of their object, so unlike standalone idents
+// they may share a name with a keyword or builtin (e.g. x.count). The second form
+// calls a method of the object.
+func (p *Parser) parseMemberExpression(object ast.Expression) (ast.Expression, error) {
+	p.trace.trace("MemberExpression")
+	defer p.trace.untrace("MemberExpression")
+
+	if object == nil {
+		return nil, newParseErr("object expression is nil", p.current)
+	}
+	memberExp := ast.MemberExpression{Token: p.current, Object: object}
+
+	if !IsIdentLiteral(p.next.Literal) {
+		msg := fmt.Sprintf("token type: have %s, want %s", p.next.Type, token.T_IDENT)
+		return nil, newParseErr(msg, p.next)
+	}
+	p.advance()
+
+	memberExp.Member = ast.Ident{Token: p.current, Value: p.current.Literal}
+	if p.next.Type != token.T_LPAREN {
+		return memberExp, nil
+	}
+
+	p.advance()
+	memberExp.Call = true
+	memberExp.LPar = p.current
+	memberExp.Args = p.parseArgs(func() (ast.Expression, error) { return p.parseExpression(precLowest) }, func(e error) error {
+		return fmt.Errorf("parseMemberExpression: %w", e)
+	})
+	memberExp.RPar = p.current // EOF if unclosed - already recorded by parseArgs.
+	return memberExp, nil
+}
+
 func (p *Parser) parseIdent() (ast.Expression, error) {
 	p.trace.trace("Ident")
 	defer p.trace.untrace("Ident")
@@ -93,7 +136,7 @@ func (p *Parser) parseIdent() (ast.Expression, error) {
 	if val == "" {
 		return ast.Ident{}, newParseErr("blank ident string", p.current)
 	}
-	if isReserved(val) {
+	if IsReserved(val) {
 		msg := fmt.Sprintf("token %s is a reserved word, and cannot be used as an identifier", val)
 		return ast.Ident{}, newParseErr(msg, p.current)
 	}
@@ -130,6 +173,10 @@ func (p *Parser) parseBooleanLiteral() (ast.Expression, error) {
 	return exp, nil
 }
 
+func (p *Parser) parseNullLiteral() (ast.Expression, error) {
+	return ast.NullLiteral{Token: p.current}, nil
+}
+
 // parsesBlockExpression advances through as many VarStatements as needed until
 // an expression is met, which must be the end of the Expression.
 //
@@ -151,7 +198,7 @@ func (p *Parser) parseBlockExpression() (ast.Expression, error) {
 			continue
 		}
 
-		exp, err := p.parseExpression()
+		exp, err := p.parseExpression(precLowest)
 		if err != nil {
 			return nil, fmt.Errorf("value expression parse error: %w", err)
 		}
@@ -164,25 +211,27 @@ func (p *Parser) parseParenExpression() (ast.Expression, error) {
 	p.trace.trace("ParenExpression")
 	defer p.trace.untrace("ParenExpression")
 
-	eWrap := func(e error) error {
-		return fmt.Errorf("ParenExpression: %w", e)
-	}
-
 	parExp := ast.ParenExpression{Token: p.current}
 	if p.next.Type == token.T_RPAREN {
 		parExp.Inner = blankExpression(p.current)
-		parExp.RParen = p.next
+		p.advance()
+		parExp.RParen = p.current
+		return parExp, nil
 	}
 
 	p.advance()
 	inner, err := p.parseBlockExpression()
 	if err != nil {
-		return nil, eWrap(err)
+		parExp.Inner = p.recoverFrom(fmt.Errorf("ParenExpression: %w", err), token.T_RPAREN)
+		parExp.RParen = p.current
+		return parExp, nil
 	}
 	parExp.Inner = inner
 
 	if err = p.wantPeek(token.T_RPAREN); err != nil {
-		return nil, eWrap(err)
+		p.recoverFrom(fmt.Errorf("ParenExpression: %w", err), token.T_RPAREN)
+		parExp.RParen = p.current
+		return parExp, nil
 	}
 	p.advance()
 
@@ -200,7 +249,7 @@ func (p *Parser) parseMacroExpression() (ast.Expression, error) {
 	defer p.trace.untrace("MacroExpression")
 
 	eWrap := func(e error) error {
-		return fmt.Errorf("parseMacroExpression: %s", e)
+		return fmt.Errorf("parseMacroExpression: %w", e)
 	}
 
 	// $<IDENT>...
@@ -222,32 +271,18 @@ func (p *Parser) parseMacroExpression() (ast.Expression, error) {
 	}
 	p.advance()
 	macro.LPar = p.current
-	p.advance()
-
-	macro.Args = []ast.Expression{}
-	for {
-		param, err := p.parseExpression()
-		if err != nil {
-			return nil, eWrap(err)
-		}
-		macro.Args = append(macro.Args, param)
-
-		if p.next.Type != token.T_COMMA {
-			break
-		}
-		p.advance()
-		p.advance()
+	macro.Args = p.parseArgs(func() (ast.Expression, error) { return p.parseExpression(precLowest) }, eWrap)
+	macro.RPar = p.current
+	if p.current.Type != token.T_RPAREN {
+		macro.RDollar = p.current // unclosed - already recorded by parseArgs.
+		return macro, nil
 	}
 
-	if err = p.wantPeek(token.T_RPAREN); err != nil {
-		return nil, eWrap(err)
-	}
-	p.advance()
-	macro.RPar = p.current
 	if err = p.wantPeek(token.T_DOLLAR); err != nil {
-		return nil, eWrap(err)
+		p.recordErr(eWrap(err))
+		macro.RDollar = p.current
+		return macro, nil
 	}
-
 	p.advance()
 	macro.RDollar = p.current
 	return macro, nil
@@ -277,33 +312,51 @@ func (p *Parser) parseBuiltinCall() (ast.Expression, error) {
 	}
 	p.advance()
 	call.LPar = p.current
-	p.advance()
-	call.Args = []ast.Expression{}
-	if p.current.Type == token.T_RPAREN {
-		call.Last = p.current
-		return call, nil
+	call.Args = p.parseArgs(p.parseBlockExpression, wrap)
+	call.Last = p.current
+	return call, nil
+}
+
+// parseArgs parses a comma separated argument list with parseArg, starting from
+// the opening parenthesis, and returns with the parser on the closing parenthesis
+// (or EOF, if unclosed).
+//
+// An argument that fails to parse is recorded, and replaced by a ParseErr in
+// the returned list, and parsing resumes with the next argument.
+func (p *Parser) parseArgs(parseArg prefixParser, wrap func(error) error) []ast.Expression {
+	args := []ast.Expression{}
+	if p.next.Type == token.T_RPAREN {
+		p.advance()
+		return args
 	}
 
 	for {
-		if p.next.Type == token.T_EOF {
+		p.advance()
+		if p.current.Type == token.T_EOF {
 			break
 		}
-		arg, err := p.parseBlockExpression()
+
+		arg, err := parseArg()
 		if err != nil {
-			return nil, wrap(err)
+			args = append(args, p.recoverFrom(wrap(err), token.T_COMMA, token.T_RPAREN))
+		} else {
+			args = append(args, arg)
+			p.advance()
+			if p.current.Type != token.T_COMMA && p.current.Type != token.T_RPAREN && p.current.Type != token.T_EOF {
+				msg := fmt.Sprintf("token type: have %s, want %s or %s", p.current.Type, token.T_COMMA, token.T_RPAREN)
+				p.recoverFrom(wrap(newParseErr(msg, p.current)), token.T_COMMA, token.T_RPAREN)
+			}
 		}
-		call.Args = append(call.Args, arg)
 
-		if p.next.Type != token.T_COMMA {
+		if p.current.Type != token.T_COMMA {
 			break
 		}
-		p.advance()
-		p.advance()
 	}
 
-	p.advance()
-	call.Last = p.current
-	return call, nil
+	if p.current.Type == token.T_EOF {
+		p.recordErr(wrap(newParseErr("missing closing parenthesis", p.current)))
+	}
+	return args
 }
 
 // parseOverExpression - looks like:
@@ -316,7 +369,7 @@ func (p *Parser) parseOverExpression() (ast.Expression, error) {
 	overExp := ast.OverExpression{Token: p.current}
 	p.advance()
 
-	ctx, err := p.parseExpression()
+	ctx, err := p.parseExpression(precLowest)
 	if err != nil {
 		return nil, fmt.Errorf("parseOverExpression: %w", err)
 	}
@@ -346,7 +399,7 @@ func (p *Parser) parseWhereExpression() (ast.Expression, error) {
 	whereExp := ast.WhereExpression{Token: p.current}
 	p.advance()
 
-	cnd, err := p.parseExpression()
+	cnd, err := p.parseExpression(precLowest)
 	if err != nil {
 		return nil, fmt.Errorf("paseOverExpression: %w", err)
 	}
@@ -370,7 +423,7 @@ func (p *Parser) parseOrderByExpression() (ast.Expression, error) {
 		return nil, wrap(err)
 	}
 
-	exp, err := p.parseExpression()
+	exp, err := p.parseExpression(precLowest)
 	if err != nil {
 		return nil, wrap(err)
 	}
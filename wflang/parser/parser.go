@@ -7,12 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
+	"github.com/scatternoodle/wflang/util"
 	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/catalog"
 	"github.com/scatternoodle/wflang/wflang/lexer"
 	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/resolve"
 	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
 )
 
 // Parser is the struct that controls the lexer and produces the AST. It is the
@@ -21,13 +26,41 @@ type Parser struct {
 	l             *lexer.Lexer
 	current       token.Token
 	next          token.Token
+	peeked        *token.Token  // a token read from the lexer ahead of next, see nextToken.
+	carried       []token.Token // comments to lead the next token, see nextToken.
 	prefixParsers map[token.Type]prefixParser
 	infixParsers  map[token.Type]infixParser
 	tokens        []token.Token
 	ast           *ast.AST
+	resolved      *resolve.Info // the names of ast, resolved once it is parsed.
 	errors        []error
 	trace         *trace
-	vars          []object.Variable
+	types         map[nodeKey]object.Object // resolved object of each node, see TypeOf
+	typeErrors    []error
+	decls         map[token.Pos]*resolve.Decl     // the declaration of each ident in resolved, by its StartPos.
+	declared      map[*resolve.Decl]object.Object // the object of each declaration evaluated so far, only used during eval
+	catalog       *catalog.Catalog                // names checked during eval, nil to check none.
+	schemas       object.Schemas                  // fields of the records bound to aliases.
+	records       types.Type                      // the records iterated by the summary function being evaluated, if any.
+}
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithCatalog checks the policy sets and pay codes of in expressions, and the
+// employee attributes of calls, against c.
+func WithCatalog(c *catalog.Catalog) Option {
+	return func(p *Parser) { p.catalog = c }
+}
+
+// WithSchemas checks the member expressions of aliases against the fields of s,
+// instead of object.DefaultSchemas. A nil s keeps the defaults.
+func WithSchemas(s object.Schemas) Option {
+	return func(p *Parser) {
+		if s != nil {
+			p.schemas = s
+		}
+	}
 }
 
 type (
@@ -35,16 +68,19 @@ type (
 	infixParser  func(ast.Expression) (ast.Expression, error)
 )
 
-// New takes a lexer, creates a new Parser, and advances it into the first token
-// within the lexer.
-func New(l *lexer.Lexer) *Parser {
+// New takes a lexer, creates a new Parser configured by opts, and advances it into
+// the first token within the lexer.
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{
 		l:             l,
 		prefixParsers: map[token.Type]prefixParser{},
 		infixParsers:  map[token.Type]infixParser{},
 		errors:        []error{},
 		trace:         &trace{0, &strings.Builder{}},
-		vars:          []object.Variable{},
+		schemas:       object.DefaultSchemas(),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
 
 	p.advance()
@@ -58,6 +94,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.prefixParsers[token.T_STRING] = p.parseStringLiteral
 	p.prefixParsers[token.T_TRUE] = p.parseBooleanLiteral
 	p.prefixParsers[token.T_FALSE] = p.parseBooleanLiteral
+	p.prefixParsers[token.T_NULL] = p.parseNullLiteral
 	p.prefixParsers[token.T_LPAREN] = p.parseParenExpression
 	p.prefixParsers[token.T_DOLLAR] = p.parseMacroExpression
 	p.prefixParsers[token.T_BUILTIN] = p.parseBuiltinCall
@@ -82,8 +119,10 @@ func New(l *lexer.Lexer) *Parser {
 	p.infixParsers[token.T_AND] = p.parseInfixExpression
 	p.infixParsers[token.T_OR] = p.parseInfixExpression
 	p.infixParsers[token.T_IN] = p.parseInExpression
+	p.infixParsers[token.T_PERIOD] = p.parseMemberExpression
 
 	p.ast = p.parse()
+	p.resolved = resolve.Resolve(p.ast, p.schemas)
 	if p.ast != nil {
 		p.eval(p.ast)
 	}
@@ -102,8 +141,10 @@ func (p *Parser) AST() (*ast.AST, error) {
 
 func (p *Parser) Errors() []error             { return p.errors }
 func (p *Parser) Tokens() []token.Token       { return p.tokens }
-func (p *Parser) Vars() []object.Variable     { return p.vars }
 func (p *Parser) Statements() []ast.Statement { return p.ast.Statements }
+func (p *Parser) Catalog() *catalog.Catalog   { return p.catalog }
+func (p *Parser) Schemas() object.Schemas     { return p.schemas }
+func (p *Parser) Resolved() *resolve.Info     { return p.resolved }
 
 // parse begins the static analysis process, producing an AST from the token stream
 // created by the lexer.
@@ -116,16 +157,39 @@ func (p *Parser) parse() *ast.AST {
 	AST := &ast.AST{Statements: []ast.Statement{}}
 
 	for p.current.Type != token.T_EOF {
+		if p.current.Trivia != nil {
+			AST.Statements = append(AST.Statements, commentStatements(p.current.Trivia.Leading)...)
+			p.current.Trivia.Leading = nil
+		}
+
+		start := p.current
 		stmt, err := p.parseStatement()
 		if err != nil {
-			err = fmt.Errorf("error parsing statement: %w", err)
-			p.errors = append(p.errors, err)
-			return &ast.AST{}
+			pErr := p.recordErr(fmt.Errorf("error parsing statement: %w", err))
+			if vs, ok := stmt.(ast.VarStatement); ok && vs.Name.Value != "" {
+				// keep the var declared, with the error in place of its value.
+				vs.Value = pErr
+				stmt = vs
+			} else {
+				stmt = pErr
+			}
+			if p.current.Type == token.T_VAR && p.current.StartPos != start.StartPos {
+				// the statement was cut short by the start of a var statement, which
+				// we can resume from directly.
+				AST.Statements = append(AST.Statements, stmt)
+				continue
+			}
+			p.synchronize()
 		}
 
 		AST.Statements = append(AST.Statements, stmt)
+		if p.current.Trivia != nil {
+			AST.Statements = append(AST.Statements, commentStatements(p.current.Trivia.Trailing)...)
+			p.current.Trivia.Trailing = nil
+		}
 		p.advance()
 	}
+	AST.Statements = append(AST.Statements, commentStatements(p.current.Leading())...)
 
 	if len(p.errors) > 0 {
 		// logging these errors is still a debug not error level because syntax errors are expected to be ubiquitous while
@@ -146,10 +210,12 @@ func (p *Parser) Trace() string {
 // advance moves the parser forward by one token.
 func (p *Parser) advance() {
 	p.current = p.next
+	p.tokens = append(p.tokens, p.current.Leading()...)
 	if p.next.Literal != "" {
 		p.tokens = append(p.tokens, p.current)
 	}
-	p.next = p.l.NextToken()
+	p.tokens = append(p.tokens, p.current.Trailing()...)
+	p.next = p.nextToken()
 }
 
 // wantPeek checks if the next token is of the expected type. If not, returns
@@ -174,8 +240,79 @@ func (p *Parser) passIf(want token.Type) error {
 	return nil
 }
 
-// isReserved returns true if string is a reserved language keyword.
-func isReserved(s string) bool {
+// recordErr adds err to the parser's errors, and returns the ParseErr that it wraps
+// so that it can be inserted into the AST in place of the node that failed to
+// parse. Errors that do not wrap a ParseErr are converted to one at the current
+// token.
+func (p *Parser) recordErr(err error) ParseErr {
+	var pErr ParseErr
+	if !errors.As(err, &pErr) {
+		pErr = newParseErr(err.Error(), p.current)
+		err = pErr
+	}
+	p.errors = append(p.errors, err)
+	return pErr
+}
+
+// recoverFrom records err, then discards tokens until the current token is one
+// of the sync types (see skipTo). Returns the ParseErr node to insert in place of
+// the node that failed to parse.
+func (p *Parser) recoverFrom(err error, sync ...token.Type) ParseErr {
+	pErr := p.recordErr(err)
+	p.skipTo(sync...)
+	return pErr
+}
+
+// skipTo advances until the current token is one of types, or EOF. Delimiters
+// opened while skipping are balanced, so a sync token nested within them does
+// not end the skip.
+func (p *Parser) skipTo(types ...token.Type) {
+	depth := 0
+	for p.current.Type != token.T_EOF {
+		if depth == 0 && slices.Contains(types, p.current.Type) {
+			return
+		}
+		switch p.current.Type {
+		case token.T_LPAREN, token.T_LBRACKET:
+			depth++
+		case token.T_RPAREN, token.T_RBRACKET:
+			if depth > 0 {
+				depth--
+			}
+		}
+		p.advance()
+	}
+}
+
+// synchronize is the statement-level counterpart of skipTo. It leaves the parser
+// on the semicolon that terminates the broken statement, or on the last token
+// before the next var statement or EOF, so that parsing can resume with the next
+// statement.
+func (p *Parser) synchronize() {
+	for p.current.Type != token.T_SEMICOLON && p.current.Type != token.T_EOF &&
+		p.next.Type != token.T_VAR && p.next.Type != token.T_EOF {
+		p.advance()
+	}
+}
+
+// IsIdentLiteral returns true if s is lexically valid as an identifier, regardless
+// of whether it is reserved.
+func IsIdentLiteral(s string) bool {
+	if s == "" || !util.IsLetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !util.IsLetter(s[i]) && !util.IsDigit(s[i]) && s[i] != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsReserved returns true if s is a reserved language keyword or the name of a
+// builtin, ignoring case.
+func IsReserved(s string) bool {
+	s = strings.ToLower(s)
 	_, isKeyword := lexer.Keyword(s)
 	_, isBuiltin := object.Builtins()[s]
 	return isKeyword || isBuiltin
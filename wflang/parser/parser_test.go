@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -30,6 +31,105 @@ func TestASTPos(t *testing.T) {
 	}
 }
 
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantErrs  int
+		wantNodes int // ParseErr nodes in the AST
+		wantStmts []reflect.Type
+	}{
+		{
+			name:      "bad var value",
+			input:     "var x = ;\nvar y = 2;",
+			wantErrs:  1,
+			wantNodes: 1,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.VarStatement{}), reflect.TypeOf(ast.VarStatement{})},
+		},
+		{
+			name:      "cut short by var",
+			input:     "x + \nvar y = 2;\ny",
+			wantErrs:  1,
+			wantNodes: 1,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ParseErr{}), reflect.TypeOf(ast.VarStatement{}), reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "empty arg",
+			input:     "min(1, , 2)",
+			wantErrs:  1,
+			wantNodes: 1,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "missing comma",
+			input:     "min(1 2, 3)",
+			wantErrs:  1,
+			wantNodes: 0,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "unclosed call",
+			input:     "min(1, 2",
+			wantErrs:  1,
+			wantNodes: 0,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "bad paren contents",
+			input:     "(1 2) + 3",
+			wantErrs:  1,
+			wantNodes: 0,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "bad macro arg",
+			input:     "$FOO(1, )$ + 1",
+			wantErrs:  1,
+			wantNodes: 1,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.ExpressionStatement{})},
+		},
+		{
+			name:      "several errors",
+			input:     "var a = );\nvar b = 1;\nmin(b, , ) + ",
+			wantErrs:  4,
+			wantNodes: 2,
+			wantStmts: []reflect.Type{reflect.TypeOf(ast.VarStatement{}), reflect.TypeOf(ast.VarStatement{}), reflect.TypeOf(ast.ParseErr{})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs, AST := testRunParser(t, tt.input, -1, true)
+			if len(prs.Errors()) != tt.wantErrs {
+				t.Errorf("have %d errors, want %d:", len(prs.Errors()), tt.wantErrs)
+				for _, err := range prs.Errors() {
+					t.Error(err.Error())
+				}
+				t.FailNow()
+			}
+
+			types := make([]reflect.Type, 0, len(AST.Statements))
+			for _, stmt := range AST.Statements {
+				types = append(types, reflect.TypeOf(stmt))
+			}
+			if !reflect.DeepEqual(types, tt.wantStmts) {
+				t.Fatalf("statements: have %v, want %v", types, tt.wantStmts)
+			}
+
+			var errNodes int
+			ast.Inspect(AST, func(n ast.Node) bool {
+				if _, ok := n.(ast.ParseErr); ok {
+					errNodes++
+				}
+				return true
+			})
+			if errNodes != tt.wantNodes {
+				t.Fatalf("ParseErr nodes: have %d, want %d", errNodes, tt.wantNodes)
+			}
+		})
+	}
+}
+
 func TestParseVarStatement(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -132,6 +232,45 @@ func TestInfixExpression(t *testing.T) {
 	}
 }
 
+func TestOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a + b * c", "(a + (b * c))"},
+		{"a * b + c", "((a * b) + c)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"a / b * c", "((a / b) * c)"},
+		{"a + b % c", "(a + (b % c))"},
+		{"-a * b", "((-a) * b)"},
+		{"!a && b", "((!a) && b)"},
+		{"a + b > c * d", "((a + b) > (c * d))"},
+		{"x = 1 && y = 2", "((x = 1) && (y = 2))"},
+		{"a != b || c <= d", "((a != b) || (c <= d))"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"a and b or c", "((a and b) or c)"},
+		{"a >= b = c < d", "(((a >= b) = c) < d)"},
+		{`a in set FOO && b`, "((a in set FOO) && b)"},
+		{`a = b in set FOO`, "(a = (b in set FOO))"},
+		{`!a in set FOO || b`, "(((!a) in set FOO) || b)"},
+		{"-x.hours * 2", "((-x.hours) * 2)"},
+		{"x.hours + y.hours", "(x.hours + y.hours)"},
+		{`x.pay_code in set FOO`, "(x.pay_code in set FOO)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, AST := testRunParser(t, tt.input, 1, false)
+
+			exp := testExpressionStatement(t, AST.Statements[0])
+			if have := exp.String(); have != tt.want {
+				t.Fatalf("String(): have %s, want %s", have, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseLineComment(t *testing.T) {
 	input := `// this is a comment
 // and another
@@ -224,6 +363,44 @@ func TestParseBlockComment(t *testing.T) {
 	}
 }
 
+func TestCommentTrivia(t *testing.T) {
+	input := `sumTime(over day alias x, // all time
+	/* hours */ x.HOURS)`
+
+	_, AST := testRunParser(t, input, 1, false)
+	call := testhelp.AssertType[ast.BuiltinCall](t, testExpressionStatement(t, AST.Statements[0]))
+	if len(call.Args) != 2 {
+		t.Fatalf("have %d arguments, want 2", len(call.Args))
+	}
+
+	// the comma is not kept in the AST, so the comment after it leads the next token.
+	member := testhelp.AssertType[ast.MemberExpression](t, call.Args[1].(ast.BlockExpression).Value)
+	obj := testhelp.AssertType[ast.Ident](t, member.Object)
+	leading := obj.Token.Leading()
+	if len(leading) != 2 || leading[0].Literal != "// all time" || leading[1].Literal != "/* hours */" {
+		t.Fatalf("leading trivia: have %+v", leading)
+	}
+	if trailing := obj.Token.Trailing(); len(trailing) != 0 {
+		t.Fatalf("trailing trivia: have %+v, want none", trailing)
+	}
+}
+
+func TestCommentTriviaTrailing(t *testing.T) {
+	input := `(var a = 1; /* one */ a) // done`
+
+	_, AST := testRunParser(t, input, 2, false)
+	paren := testhelp.AssertType[ast.ParenExpression](t, testExpressionStatement(t, AST.Statements[0]))
+	block := testhelp.AssertType[ast.BlockExpression](t, paren.Inner)
+	if trailing := block.Vars[0].Semicolon.Trailing(); len(trailing) != 1 || trailing[0].Literal != "/* one */" {
+		t.Fatalf("trailing trivia: have %+v", trailing)
+	}
+	// comments after the last token of a statement are statements of their own.
+	testhelp.AssertType[ast.LineCommentStatement](t, AST.Statements[1])
+	if trailing := paren.RParen.Trailing(); len(trailing) != 0 {
+		t.Fatalf("trailing trivia: have %+v, want none", trailing)
+	}
+}
+
 func TestStringLiteral(t *testing.T) {
 	input := `"hello"`
 	_, AST := testRunParser(t, input, 1, false)
@@ -258,6 +435,15 @@ func TestBooleanLiteral(t *testing.T) {
 	}
 }
 
+func TestNullLiteral(t *testing.T) {
+	for _, input := range []string{"null", "NULL"} {
+		t.Run(input, func(t *testing.T) {
+			_, AST := testRunParser(t, input, 1, false)
+			testhelp.AssertType[ast.NullLiteral](t, testExpressionStatement(t, AST.Statements[0]))
+		})
+	}
+}
+
 func TestParenExpression(t *testing.T) {
 	input := `(
 	var x = "foo";
@@ -316,6 +502,47 @@ func TestMacroExpression(t *testing.T) {
 	}
 }
 
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantObject string
+		wantMember string
+		wantErr    bool
+	}{
+		{"x.hours", "x", "hours", false},
+		{"period.end", "period", "end", false},
+		{"x.count", "x", "count", false},
+		{"a.b.c", "a.b", "c", false},
+		{"x.hasTimes()", "x", "hasTimes", false},
+		{`x.isSeverity("HIGH", 1)`, "x", "isSeverity", false},
+		{"x.a().b", "x.a()", "b", false},
+		{"x.", "", "", true},
+		{"x.a(", "", "", true},
+		{"x.1", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, AST := testRunParser(t, tt.input, 1, tt.wantErr)
+			if tt.wantErr {
+				return
+			}
+
+			exp := testExpressionStatement(t, AST.Statements[0])
+			mExp := testhelp.AssertType[ast.MemberExpression](t, exp)
+			if have := mExp.Object.String(); have != tt.wantObject {
+				t.Errorf("Object: have %s, want %s", have, tt.wantObject)
+			}
+			if have := mExp.Member.Value; have != tt.wantMember {
+				t.Errorf("Member: have %s, want %s", have, tt.wantMember)
+			}
+			if have := mExp.String(); have != tt.input {
+				t.Errorf("String(): have %s, want %s", have, tt.input)
+			}
+		})
+	}
+}
+
 func TestOverExpression(t *testing.T) {
 	input := `over day`
 	_, AST := testRunParser(t, input, 1, false)
@@ -0,0 +1,53 @@
+package parser
+
+import "github.com/scatternoodle/wflang/wflang/token"
+
+// Operator precedence levels, from loosest to tightest binding. parseExpression
+// keeps consuming infix operators for as long as the next operator binds tighter
+// than the level it was called with.
+const (
+	_ int = iota
+	precLowest
+	precOr      // || or
+	precAnd     // && and
+	precCompare // = != < > <= >=
+	precIn      // in
+	precSum     // + -
+	precProduct // * / %
+	precPrefix  // -x !x not x
+	precMember  // x.y
+)
+
+// precedences maps the infix operators to their precedence.
+var precedences = map[token.Type]int{
+	token.T_OR:       precOr,
+	token.T_AND:      precAnd,
+	token.T_EQ:       precCompare,
+	token.T_NEQ:      precCompare,
+	token.T_LT:       precCompare,
+	token.T_GT:       precCompare,
+	token.T_LTE:      precCompare,
+	token.T_GTE:      precCompare,
+	token.T_IN:       precIn,
+	token.T_PLUS:     precSum,
+	token.T_MINUS:    precSum,
+	token.T_ASTERISK: precProduct,
+	token.T_SLASH:    precProduct,
+	token.T_MODULO:   precProduct,
+	token.T_PERIOD:   precMember,
+}
+
+// precedenceOf returns the infix precedence of token type t, or precLowest if t
+// is not an infix operator.
+func precedenceOf(t token.Type) int {
+	if prec, ok := precedences[t]; ok {
+		return prec
+	}
+	return precLowest
+}
+
+// peekPrecedence returns the infix precedence of the next token.
+func (p *Parser) peekPrecedence() int { return precedenceOf(p.next.Type) }
+
+// currentPrecedence returns the infix precedence of the current token.
+func (p *Parser) currentPrecedence() int { return precedenceOf(p.current.Type) }
@@ -13,10 +13,6 @@ func (p *Parser) parseStatement() (ast.Statement, error) {
 	switch p.current.Type {
 	case token.T_VAR:
 		return p.parseVarStatement()
-	case token.T_COMMENT_LINE:
-		return p.parseLineCommentStatement(), nil
-	case token.T_COMMENT_BLOCK:
-		return p.parseBlockCommentStatement(), nil
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -30,7 +26,7 @@ func (p *Parser) parseExpressionStatement() (ast.ExpressionStatement, error) {
 	defer p.trace.untrace("ExpressionStatement")
 
 	stmt := ast.ExpressionStatement{Token: p.current}
-	exp, err := p.parseExpression()
+	exp, err := p.parseExpression(precLowest)
 	if err != nil {
 		err = fmt.Errorf("error parsing expression: %w", err)
 		return ast.ExpressionStatement{}, err
@@ -42,6 +38,9 @@ func (p *Parser) parseExpressionStatement() (ast.ExpressionStatement, error) {
 // parseVarStatement resolves a statement following this format:
 //
 //	var [T_IDENT] = [Expression];
+//
+// If the value fails to parse, the statement is returned with its name along with
+// the error.
 func (p *Parser) parseVarStatement() (ast.VarStatement, error) {
 	p.trace.trace("VarStatement")
 	defer p.trace.untrace("VarStatement")
@@ -71,9 +70,10 @@ func (p *Parser) parseVarStatement() (ast.VarStatement, error) {
 	p.advance()
 
 	// ...[Expression]
-	exp, err := p.parseExpression()
+	exp, err := p.parseExpression(precLowest)
 	if err != nil {
-		return ast.VarStatement{}, eWrap(err)
+		// the name is still returned, so that the var remains declared.
+		return stmt, eWrap(err)
 	}
 	stmt.Value = exp
 
@@ -85,17 +85,3 @@ func (p *Parser) parseVarStatement() (ast.VarStatement, error) {
 	stmt.Semicolon = p.current
 	return stmt, nil
 }
-
-func (p *Parser) parseLineCommentStatement() ast.LineCommentStatement {
-	p.trace.trace("LineCommentStatement")
-	defer p.trace.untrace("LineCommentStatement")
-
-	return ast.LineCommentStatement{Token: p.current}
-}
-
-func (p *Parser) parseBlockCommentStatement() ast.BlockCommentStatement {
-	p.trace.trace("BlockCommentStatement")
-	defer p.trace.untrace("BlockCommentStatement")
-
-	return ast.BlockCommentStatement{Token: p.current}
-}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// detached returns the types of tokens that are not kept in the AST. Comments
+// around them are carried forward to the next token, so that they are not lost.
+func detached() []token.Type {
+	return []token.Type{token.T_COMMA, token.T_EQ, token.T_BY, token.T_DOLLAR}
+}
+
+// nextToken returns the next non-comment token from the lexer, with the comments
+// around it attached as trivia. Comments that start on the line the token ends on
+// trail it, and all others lead the token after them.
+func (p *Parser) nextToken() token.Token {
+	leading := p.carried
+	p.carried = nil
+
+	tok := p.lex()
+	for tok.IsComment() {
+		leading = append(leading, tok)
+		tok = p.lex()
+	}
+
+	var trailing []token.Token
+	for tok.Type != token.T_EOF {
+		next := p.lex()
+		if !next.IsComment() || next.StartPos.Line != tok.EndPos.Line && !continues(trailing, next) {
+			p.peeked = &next
+			break
+		}
+		trailing = append(trailing, next)
+	}
+
+	if slices.Contains(detached(), tok.Type) {
+		p.carried = append(leading, trailing...)
+		return tok
+	}
+	if len(leading) > 0 || len(trailing) > 0 {
+		tok.Trivia = &token.Trivia{Leading: leading, Trailing: trailing}
+	}
+	return tok
+}
+
+// lex returns the next token from the lexer, including comments.
+func (p *Parser) lex() token.Token {
+	if p.peeked != nil {
+		tok := *p.peeked
+		p.peeked = nil
+		return tok
+	}
+	return p.l.NextToken()
+}
+
+// continues returns true if tok is a further line of the block comment that ends
+// comments, as the lexer splits block comments into a token per line.
+func continues(comments []token.Token, tok token.Token) bool {
+	if len(comments) == 0 || tok.Type != token.T_COMMENT_BLOCK {
+		return false
+	}
+	last := comments[len(comments)-1]
+	return last.Type == token.T_COMMENT_BLOCK && !strings.HasSuffix(last.Literal, "*/") &&
+		!strings.HasPrefix(strings.TrimSpace(tok.Literal), "/*")
+}
+
+// commentStatements returns the comments as statements. Comments between
+// statements are kept as statements rather than trivia.
+func commentStatements(comments []token.Token) []ast.Statement {
+	stmts := make([]ast.Statement, len(comments))
+	for i, c := range comments {
+		if c.Type == token.T_COMMENT_LINE {
+			stmts[i] = ast.LineCommentStatement{Token: c}
+		} else {
+			stmts[i] = ast.BlockCommentStatement{Token: c}
+		}
+	}
+	return stmts
+}
@@ -0,0 +1,37 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+// Stable diagnostic codes of Error.
+const (
+	CodeUndefined     string = "undefined"
+	CodeUseBeforeDecl string = "use-before-declaration"
+)
+
+// Codes returns the stable diagnostic codes of Error.
+func Codes() []string { return []string{CodeUndefined, CodeUseBeforeDecl} }
+
+// Error is an identifier that cannot be resolved to a declaration, or that is used
+// before its declaration.
+type Error struct {
+	Msg     string
+	Node    ast.Node
+	ErrCode string // one of the Code consts.
+}
+
+func newError(node ast.Node, code, format string, a ...any) Error {
+	return Error{Msg: fmt.Sprintf(format, a...), Node: node, ErrCode: code}
+}
+
+func (e Error) Error() string { return e.Msg }
+
+// Code returns the stable diagnostic code of the error.
+func (e Error) Code() string { return e.ErrCode }
+
+// Pos returns the StartPos and EndPos of the identifier.
+func (e Error) Pos() (start, end token.Pos) { return e.Node.Pos() }
@@ -0,0 +1,393 @@
+// Package resolve binds the identifiers of a formula to the declarations of the
+// vars and aliases they refer to. Scopes form a tree: the file, each block
+// expression that declares vars, and each summary function that declares an alias.
+// Names are case insensitive, and a declaration is visible after it ends, so a var
+// cannot refer to itself.
+package resolve
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/object"
+	"github.com/scatternoodle/wflang/wflang/token"
+	"github.com/scatternoodle/wflang/wflang/types"
+)
+
+// Kind is the kind of a declaration.
+type Kind int
+
+const (
+	KindVar   Kind = iota // declared by a VarStatement.
+	KindAlias             // declared by the over clause of a summary function.
+)
+
+func (k Kind) String() string {
+	if k == KindAlias {
+		return "alias"
+	}
+	return "var"
+}
+
+// Decl is the declaration of a var or an alias.
+type Decl struct {
+	Kind    Kind
+	Name    ast.Ident        // the declaring ident.
+	Stmt    ast.VarStatement // the statement declaring a var, zero for aliases.
+	Call    ast.BuiltinCall  // the summary function declaring an alias, zero for vars.
+	Records types.Type       // the records bound to an alias, empty if it is not bound to records.
+	Scope   *Scope
+}
+
+// end returns the position after which d is visible.
+func (d *Decl) end() token.Pos {
+	_, end := d.Name.Pos()
+	if d.Kind == KindVar {
+		if d.Stmt.Value != nil {
+			_, end = d.Stmt.Value.Pos()
+		}
+		if d.Stmt.Semicolon.EndPos.GT(end) {
+			end = d.Stmt.Semicolon.EndPos
+		}
+	}
+	return end
+}
+
+// Scope is a region of a formula in which names can be declared.
+type Scope struct {
+	Node     ast.Node // the *ast.AST, ast.BlockExpression or ast.BuiltinCall that opens the scope.
+	Parent   *Scope   // nil for the file.
+	Children []*Scope
+	Decls    []*Decl // in order of declaration.
+}
+
+// Local returns the declaration of name in s that is visible at pos, which is the
+// last declared before it.
+func (s *Scope) Local(name string, pos token.Pos) (*Decl, bool) {
+	return s.local(name, pos, declName)
+}
+
+// Lookup returns the declaration of name visible at pos from s, searching from s
+// outward.
+func (s *Scope) Lookup(name string, pos token.Pos) (*Decl, bool) {
+	return s.lookup(name, pos, declName)
+}
+
+// local is Local with the name of each declaration given by nameOf.
+func (s *Scope) local(name string, pos token.Pos, nameOf func(*Decl) string) (*Decl, bool) {
+	for i := len(s.Decls) - 1; i >= 0; i-- {
+		if d := s.Decls[i]; strings.EqualFold(nameOf(d), name) && d.end().LT(pos) {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// lookup is Lookup with the name of each declaration given by nameOf.
+func (s *Scope) lookup(name string, pos token.Pos, nameOf func(*Decl) string) (*Decl, bool) {
+	for ; s != nil; s = s.Parent {
+		if d, ok := s.local(name, pos, nameOf); ok {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+func declName(d *Decl) string { return d.Name.Value }
+
+// declaredLater returns a declaration of name in s or a scope enclosing it, for a
+// use that comes before any of them is visible.
+func (s *Scope) declaredLater(name string) (*Decl, bool) {
+	for ; s != nil; s = s.Parent {
+		for _, d := range s.Decls {
+			if strings.EqualFold(d.Name.Value, name) {
+				return d, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Ref is a use of a name, in the scope it appears in.
+type Ref struct {
+	Ident   ast.Ident
+	Decl    *Decl // nil for names that are not declared.
+	Scope   *Scope
+	Records types.Type // iterated by the innermost summary function that iterates records, if any.
+}
+
+// Info is the result of resolving a formula.
+type Info struct {
+	Root   *Scope  // the scope of the file.
+	Decls  []*Decl // in order of position.
+	Refs   []Ref   // uses of declared names, in order of position.
+	Free   []Ref   // uses of globals, fields and undefined names, in order of position.
+	Errors []error // of type Error.
+
+	schemas object.Schemas // the fields that undeclared names may refer to.
+}
+
+// At returns the declaration that the ident at pos declares or refers to.
+func (info *Info) At(pos token.Pos) (*Decl, bool) {
+	for _, d := range info.Decls {
+		if contains(d.Name, pos) {
+			return d, true
+		}
+	}
+	for _, ref := range info.Refs {
+		if contains(ref.Ident, pos) {
+			return ref.Decl, true
+		}
+	}
+	return nil, false
+}
+
+// RefsTo returns the idents that refer to d, not including its declaration.
+func (info *Info) RefsTo(d *Decl) []ast.Ident {
+	var idents []ast.Ident
+	for _, ref := range info.Refs {
+		if ref.Decl == d {
+			idents = append(idents, ref.Ident)
+		}
+	}
+	return idents
+}
+
+// Conflict returns an ident whose meaning would change if d were renamed to name:
+// another declaration of name in the scope of d, or a use that would then refer to
+// a different declaration, or to d in place of a global or field. A use of d within
+// a summary function whose records have a field called name also conflicts, as it
+// would then read as the field.
+func (info *Info) Conflict(d *Decl, name string) (ast.Ident, bool) {
+	if strings.EqualFold(name, d.Name.Value) {
+		return ast.Ident{}, false
+	}
+	for _, other := range d.Scope.Decls {
+		if other != d && strings.EqualFold(other.Name.Value, name) {
+			return other.Name, true
+		}
+	}
+
+	renamed := func(e *Decl) string {
+		if e == d {
+			return name
+		}
+		return e.Name.Value
+	}
+	for _, ref := range slices.Concat(info.Refs, info.Free) {
+		if ref.Decl != nil {
+			// uses before their declaration are bound to it whatever its name.
+			if bound, ok := ref.Scope.Lookup(ref.Ident.Value, ref.Ident.StartPos); !ok || bound != ref.Decl {
+				continue
+			}
+		}
+		refName := ref.Ident.Value
+		if ref.Decl == d {
+			refName = name
+		}
+		if bound, _ := ref.Scope.lookup(refName, ref.Ident.StartPos, renamed); bound != ref.Decl {
+			return ref.Ident, true
+		}
+	}
+	for _, ref := range info.Refs {
+		if _, ok := info.schemas.Field(ref.Records, name); ok && ref.Decl == d {
+			return ref.Ident, true
+		}
+	}
+	return ast.Ident{}, false
+}
+
+func contains(id ast.Ident, pos token.Pos) bool {
+	return id.StartPos.LTE(pos) && pos.LTE(id.EndPos)
+}
+
+// globals are the summary contexts that are always in scope.
+var globals = []string{"day", "week", "period"}
+
+// Resolve resolves the identifiers of root. Within summary functions that iterate
+// records, identifiers that are not declared may also name the fields of the
+// record, as described by schemas, or object.DefaultSchemas if it is nil.
+func Resolve(root *ast.AST, schemas object.Schemas) *Info {
+	if schemas == nil {
+		schemas = object.DefaultSchemas()
+	}
+	r := &resolver{info: &Info{Root: &Scope{Node: root}, schemas: schemas}}
+	r.scope = r.info.Root
+	if root != nil {
+		ast.Inspect(root, r.visit)
+	}
+
+	for _, u := range r.uses {
+		name := u.ident.Value
+		if d, ok := u.scope.Lookup(name, u.ident.StartPos); ok {
+			r.info.Refs = append(r.info.Refs, Ref{Ident: u.ident, Decl: d, Scope: u.scope, Records: u.records})
+			continue
+		}
+		if d, ok := u.scope.declaredLater(name); ok {
+			r.info.Refs = append(r.info.Refs, Ref{Ident: u.ident, Decl: d, Scope: u.scope, Records: u.records})
+			r.errorf(u.ident, CodeUseBeforeDecl, "%s %s used before it is declared", d.Kind, name)
+			continue
+		}
+		r.info.Free = append(r.info.Free, Ref{Ident: u.ident, Scope: u.scope, Records: u.records})
+		if slices.Contains(globals, strings.ToLower(name)) {
+			continue
+		}
+		if _, ok := schemas.Field(u.records, name); ok {
+			continue
+		}
+		r.errorf(u.ident, CodeUndefined, "undefined: %s", name)
+	}
+
+	slices.SortFunc(r.info.Decls, func(a, b *Decl) int { return compare(a.Name.StartPos, b.Name.StartPos) })
+	byPos := func(a, b Ref) int { return compare(a.Ident.StartPos, b.Ident.StartPos) }
+	slices.SortFunc(r.info.Refs, byPos)
+	slices.SortFunc(r.info.Free, byPos)
+	return r.info
+}
+
+func compare(a, b token.Pos) int {
+	switch {
+	case a.LT(b):
+		return -1
+	case a.GT(b):
+		return 1
+	}
+	return 0
+}
+
+// resolver collects the declarations and uses of names while walking the AST. Uses
+// are resolved once the walk is done, so that those that come before their
+// declaration can be told apart from those that are undefined.
+type resolver struct {
+	info    *Info
+	scope   *Scope
+	call    ast.BuiltinCall // the innermost call, whose over clause declares its alias.
+	records []types.Type    // iterated by the enclosing summary functions, innermost last.
+	uses    []use
+}
+
+// use is an ident that refers to a name, in the scope it appears in.
+type use struct {
+	ident   ast.Ident
+	scope   *Scope
+	records types.Type // iterated by the innermost summary function that iterates records.
+}
+
+func (r *resolver) visit(n ast.Node) bool {
+	switch v := n.(type) {
+	case ast.VarStatement:
+		r.inspect(v.Value)
+		r.declare(&Decl{Kind: KindVar, Name: v.Name, Stmt: v})
+		return false
+
+	case ast.BlockExpression:
+		if len(v.Vars) > 0 {
+			defer r.open(v)()
+		}
+		for _, vs := range v.Vars {
+			r.visit(vs)
+		}
+		r.inspect(v.Value)
+		return false
+
+	case ast.BuiltinCall:
+		r.visitCall(v)
+		return false
+
+	case ast.OverExpression:
+		r.inspect(v.Context)
+		if v.HasAlias {
+			fn, _ := object.Builtin(r.call.Name)
+			r.declare(&Decl{Kind: KindAlias, Name: v.Alias.Alias, Call: r.call, Records: fn.Records})
+		}
+		return false
+
+	case ast.MemberExpression:
+		// the member is a field, property or method, never a name.
+		r.inspect(v.Object)
+		for _, arg := range v.Args {
+			r.inspect(arg)
+		}
+		return false
+
+	case ast.Ident:
+		u := use{ident: v, scope: r.scope}
+		if len(r.records) > 0 {
+			u.records = r.records[len(r.records)-1]
+		}
+		r.uses = append(r.uses, u)
+	}
+	return true
+}
+
+// visitCall visits the arguments of call, in a scope of their own if it declares
+// an alias. Arguments that are identifiers of something other than a name, such
+// as the ID of an employee attribute, are not uses.
+func (r *resolver) visitCall(call ast.BuiltinCall) {
+	fn, _ := object.Builtin(call.Name)
+	if hasAlias(call) {
+		defer r.open(call)()
+	}
+	if fn.Records != "" {
+		r.records = append(r.records, fn.Records)
+		defer func() { r.records = r.records[:len(r.records)-1] }()
+	}
+	outer := r.call
+	r.call = call
+	defer func() { r.call = outer }()
+
+	for i, arg := range call.Args {
+		if _, isIdent := ast.Unblock(arg).(ast.Ident); isIdent && identParam(fn, i) {
+			continue
+		}
+		r.inspect(arg)
+	}
+}
+
+// open opens a scope for n within the current scope, and returns a func that
+// closes it.
+func (r *resolver) open(n ast.Node) (close func()) {
+	s := &Scope{Node: n, Parent: r.scope}
+	r.scope.Children = append(r.scope.Children, s)
+	r.scope = s
+	return func() { r.scope = s.Parent }
+}
+
+func (r *resolver) declare(d *Decl) {
+	d.Scope = r.scope
+	r.scope.Decls = append(r.scope.Decls, d)
+	r.info.Decls = append(r.info.Decls, d)
+}
+
+func (r *resolver) inspect(n ast.Node) {
+	if n != nil {
+		ast.Inspect(n, r.visit)
+	}
+}
+
+func (r *resolver) errorf(n ast.Node, code, format string, a ...any) {
+	r.info.Errors = append(r.info.Errors, newError(n, code, format, a...))
+}
+
+// hasAlias returns true if the over clause of call declares an alias.
+func hasAlias(call ast.BuiltinCall) bool {
+	return slices.ContainsFunc(call.Args, func(arg ast.Expression) bool {
+		over, ok := ast.Unblock(arg).(ast.OverExpression)
+		return ok && over.HasAlias
+	})
+}
+
+// identParam returns true if the i-th argument of fn is an identifier that is not
+// a name, such as the ID of an employee attribute. Arguments beyond the params
+// take the last, if it is a list.
+func identParam(fn object.Function, i int) bool {
+	if len(fn.Params) == 0 {
+		return false
+	}
+	param := fn.Params[min(i, len(fn.Params)-1)]
+	if i >= len(fn.Params) && !param.List {
+		return false
+	}
+	return slices.Equal(param.Types, []types.Type{types.T_IDENT})
+}
@@ -0,0 +1,146 @@
+package resolve_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/resolve"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantRefs []string // "col->col" of each ref and the name of its declaration.
+		wantErrs []string // "code col" of each error.
+	}{
+		{"var", `var a = 1; a + A`, []string{"11->4", "15->4"}, nil},
+		{"blocks", `(var x = 1; x) + (var x = 2; x)`, []string{"12->5", "29->22"}, nil},
+		{"inner and outer", `var x = 1; (var x = 2; x) + x`, []string{"23->16", "28->4"}, nil},
+		{"var in a var", `var a = (var b = 1; b); a`, []string{"20->13", "24->4"}, nil},
+		{"alias", `sumTime(over day alias t, t.HOURS)`, []string{"26->23"}, nil},
+		{"alias hides var", `var t = 1; sumTime(over day alias t, t.HOURS) + t`, []string{"37->34", "48->4"}, nil},
+		{"undefined", `y + 1`, nil, []string{"undefined 0"}},
+		{"block var out of scope", `if(true, (var y = 1; y), 2) + y`, []string{"21->14"}, []string{"undefined 30"}},
+		{"use before declaration", `var a = b; var b = 1; a`, []string{"8->15", "22->4"}, []string{"use-before-declaration 8"}},
+		{"self reference", `var x = x + 1; x`, []string{"8->4", "15->4"}, []string{"use-before-declaration 8"}},
+		{"bare field", `sumTime(over day, HOURS)`, nil, nil},
+		{"bare field of another record", `countException(over day, where HOURS > 1)`, nil, []string{"undefined 31"}},
+		{"globals", `count(over week alias w, where true) + count(over period, where true)`, nil, nil},
+		{"attribute id", `employee_attribute(HOURLY_RATE, day)`, nil, nil},
+		{"method args", `var s = "HIGH"; countException(over day alias e, where e.isSeverity(s))`, []string{"55->46", "68->4"}, nil},
+		{"policy set and member", `count(over day alias d, where d.pay_code in set OT)`, []string{"30->21"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs := parser.New(lexer.New(tt.input))
+			if len(prs.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", prs.Errors())
+			}
+			root, _ := prs.AST()
+			info := resolve.Resolve(root, nil)
+
+			var refs, errs []string
+			for _, ref := range info.Refs {
+				refs = append(refs, fmt.Sprintf("%d->%d", ref.Ident.StartPos.Col, ref.Decl.Name.StartPos.Col))
+			}
+			for _, err := range info.Errors {
+				rErr := err.(resolve.Error)
+				start, _ := rErr.Pos()
+				errs = append(errs, fmt.Sprintf("%s %d", rErr.Code(), start.Col))
+			}
+			if strings.Join(refs, ", ") != strings.Join(tt.wantRefs, ", ") {
+				t.Errorf("refs: have %v, want %v", refs, tt.wantRefs)
+			}
+			if strings.Join(errs, ", ") != strings.Join(tt.wantErrs, ", ") {
+				t.Errorf("errors: have %v %v, want %v", errs, info.Errors, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	input := "var a = 1;\n(var b = 2; sumTime(over day alias t, t.HOURS + b)) + a"
+	prs := parser.New(lexer.New(input))
+	root, _ := prs.AST()
+	info := resolve.Resolve(root, nil)
+
+	if len(info.Root.Decls) != 1 || info.Root.Decls[0].Name.Value != "a" {
+		t.Fatalf("file scope: have %v, want a", info.Root.Decls)
+	}
+	if len(info.Root.Children) != 1 {
+		t.Fatalf("file scope: have %d children, want 1", len(info.Root.Children))
+	}
+	block := info.Root.Children[0]
+	if len(block.Decls) != 1 || block.Decls[0].Name.Value != "b" || len(block.Children) != 1 {
+		t.Fatalf("block scope: have %v with %d children, want b with 1", block.Decls, len(block.Children))
+	}
+	call := block.Children[0]
+	if len(call.Decls) != 1 || call.Decls[0].Kind != resolve.KindAlias || call.Decls[0].Records != "timeRecord" {
+		t.Fatalf("call scope: have %v, want the alias t of time records", call.Decls)
+	}
+
+	if d, ok := call.Lookup("A", token.Pos{Line: 1, Col: 40}); !ok || d != info.Root.Decls[0] {
+		t.Errorf("Lookup: have %v %t, want a", d, ok)
+	}
+	if _, ok := block.Local("a", token.Pos{Line: 1, Col: 40}); ok {
+		t.Error("Local: found a outside its scope")
+	}
+
+	d, ok := info.At(token.Pos{Line: 1, Col: 48})
+	if !ok || d.Name.Value != "b" {
+		t.Fatalf("At: have %v %t, want b", d, ok)
+	}
+	if refs := info.RefsTo(d); len(refs) != 1 || refs[0].StartPos != (token.Pos{Line: 1, Col: 48}) {
+		t.Errorf("RefsTo: have %v, want the use at 1:48", refs)
+	}
+}
+
+func TestConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		col     uint // of the declaration to rename.
+		newName string
+		wantCol int // of the conflicting ident, -1 for none.
+	}{
+		{"no conflict", `var a = 1; var b = 2; a + b`, 4, "c", -1},
+		{"same name", `var a = 1; a`, 4, "A", -1},
+		{"same scope", `var a = 1; var b = 2; a + b`, 4, "B", 15},
+		{"other scope", `(var a = 1; a) + (var b = 2; b)`, 5, "b", -1},
+		{"shadowed by inner", `var a = 1; (var b = 2; a + b)`, 4, "b", 23},
+		{"shadows outer", `var a = 1; (var b = 2; a + b)`, 16, "a", 23},
+		{"hides global", `var a = 1; count(over day, where a > 1)`, 4, "day", 22},
+		{"hides field", `var a = 1; sumTime(over day, HOURS) + a`, 4, "hours", 29},
+		{"alias hides field", `sumTime(over day alias t, HOURS + t.HOURS)`, 23, "hours", 26},
+		{"captures undefined", `var a = 1; a + y`, 4, "y", 15},
+		{"reads as field", `var h = 1; sumTime(over day alias x, h)`, 4, "hours", 37},
+		{"field outside records", `var h = 1; sumTime(over day alias x, x.HOURS) + h`, 4, "hours", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs := parser.New(lexer.New(tt.input))
+			root, _ := prs.AST()
+			info := resolve.Resolve(root, nil)
+			d, ok := info.At(token.Pos{Col: tt.col})
+			if !ok {
+				t.Fatalf("no declaration at col %d", tt.col)
+			}
+
+			ident, ok := info.Conflict(d, tt.newName)
+			have := -1
+			if ok {
+				have = int(ident.StartPos.Col)
+			}
+			if have != tt.wantCol {
+				t.Errorf("have conflict at col %d, want %d", have, tt.wantCol)
+			}
+		})
+	}
+}
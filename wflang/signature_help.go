@@ -3,9 +3,9 @@ package wflang
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/scatternoodle/wflang/internal/lsp"
-	"github.com/scatternoodle/wflang/server/docstring"
 	"github.com/scatternoodle/wflang/wflang/ast"
 	"github.com/scatternoodle/wflang/wflang/object"
 	"github.com/scatternoodle/wflang/wflang/token"
@@ -58,24 +58,26 @@ func SignatureHelp(root ast.Node, pos token.Pos) (*lsp.SignatureInfo, int, error
 		return &lsp.SignatureInfo{}, 0, err
 	}
 
-	// get the function documentation and start building the signature info
-	doc, ok := docstring.BuiltinDoc(function.Name)
-	if !ok {
-		err = fmt.Errorf("FunctionDoc missing for builtin %s", function.Name)
-		slog.Error(err.Error())
-		return &lsp.SignatureInfo{}, 0, err
-	}
-	paramInfos := make([]lsp.ParamInfo, len(doc.Params))
-	for i, pDoc := range doc.Params {
-		paramInfos[i] = lsp.ParamInfo{
+	// build the signature info from the function documentation. Inline params are
+	// part of the previous argument, so are not given param infos of their own.
+	doc := function.Doc()
+	paramInfos := make([]lsp.ParamInfo, 0, len(doc.Params))
+	for _, pDoc := range doc.Params {
+		if pDoc.Inline {
+			continue
+		}
+		paramInfos = append(paramInfos, lsp.ParamInfo{
 			Label: pDoc.Label,
 			Documentation: &lsp.MarkupContent{
 				Kind:  lsp.MarkupKindMarkdown,
 				Value: pDoc.String(),
 			},
-		}
+		})
 	}
-	if len(callable.Params()) > len(paramInfos) {
+	// calls to functions with list params, or whose params are unknown, can have any
+	// number of arguments.
+	variadic := function.Params == nil || slices.ContainsFunc(function.Params, func(p object.Param) bool { return p.List })
+	if !variadic && len(callable.Params()) > len(paramInfos) {
 		err = fmt.Errorf("callable param len %d exceeds stored param info len %d for the function",
 			len(callable.Params()), len(paramInfos))
 		slog.Error(err.Error())
@@ -1 +1,46 @@
 package wflang
+
+import (
+	"testing"
+
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/parser"
+	"github.com/scatternoodle/wflang/wflang/token"
+)
+
+func TestSignatureHelp(t *testing.T) {
+	tests := []struct {
+		input      string
+		col        uint
+		wantLabel  string
+		wantParams int
+		wantActive int
+	}{
+		{`contains("abc", "b")`, 10, "contains(x: string, y: string)", 2, 0},
+		{`contains("abc", "b")`, 18, "contains(x: string, y: string)", 2, 1},
+		{`min(1, 2, 3, 4)`, 14, "min(args: number...)", 1, 0},
+		{`sumTime(over day alias x, x.hours)`, 30, "sumTime(over range: day|week|period|dateRange alias aliasName?: string, expression: number, where condition?: boolean)", 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			root, err := parser.New(lexer.New(tt.input)).AST()
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, active, err := SignatureHelp(root, token.Pos{Line: 0, Col: tt.col})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Label != tt.wantLabel {
+				t.Errorf("label: have %q, want %q", info.Label, tt.wantLabel)
+			}
+			if len(info.Params) != tt.wantParams {
+				t.Errorf("params: have %d, want %d", len(info.Params), tt.wantParams)
+			}
+			if active != tt.wantActive {
+				t.Errorf("active param: have %d, want %d", active, tt.wantActive)
+			}
+		})
+	}
+}
@@ -6,11 +6,41 @@ import (
 
 // Token represents a word, or "semantic token" in WFLang.
 type Token struct {
-	Type     Type   // The token type - see "T_" consts in this pkg.
-	Literal  string // The token expressed as a string literal.
-	StartPos Pos    // The starting position of the token.
-	EndPos   Pos    // The ending position of the token.
-	Len      int    // The length of the token, in bytes.
+	Type     Type    // The token type - see "T_" consts in this pkg.
+	Literal  string  // The token expressed as a string literal.
+	StartPos Pos     // The starting position of the token.
+	EndPos   Pos     // The ending position of the token.
+	Len      int     // The length of the token, in bytes.
+	Offset   uint    // The byte offset of StartPos within the input.
+	Trivia   *Trivia // Comments attached to the token by the parser, nil if there are none.
+}
+
+// Trivia holds the comment tokens surrounding a token. Trailing comments start on
+// the line that the token ends on, and leading comments are all others before it.
+type Trivia struct {
+	Leading  []Token
+	Trailing []Token
+}
+
+// Leading returns the comments before the token.
+func (tk Token) Leading() []Token {
+	if tk.Trivia == nil {
+		return nil
+	}
+	return tk.Trivia.Leading
+}
+
+// Trailing returns the comments after the token on the same line.
+func (tk Token) Trailing() []Token {
+	if tk.Trivia == nil {
+		return nil
+	}
+	return tk.Trivia.Trailing
+}
+
+// IsComment returns true if the token is a line or block comment.
+func (tk Token) IsComment() bool {
+	return tk.Type == T_COMMENT_LINE || tk.Type == T_COMMENT_BLOCK
 }
 
 // Valid returns true if the token is a zero-value, in which case, uniquely, the
@@ -0,0 +1,60 @@
+package wftest
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+// Layouts that date and time results are printed and matched in.
+const (
+	dateLayout     = "2006-01-02"
+	timeLayout     = "15:04"
+	dateTimeLayout = "2006-01-02T15:04"
+)
+
+// format prints a result the way it would be written as the want of a case.
+func format(obj object.Object) string {
+	switch o := obj.(type) {
+	case object.Null:
+		return "null"
+	case object.Number:
+		return strconv.FormatFloat(o.Val, 'f', -1, 64)
+	case object.Boolean:
+		return strconv.FormatBool(o.Val)
+	case object.String:
+		return strconv.Quote(o.Val)
+	case object.Date:
+		return strconv.Quote(o.Val.Format(dateLayout))
+	case object.Time:
+		return strconv.Quote(o.Val.Format(timeLayout))
+	case object.DateTime:
+		return strconv.Quote(o.Val.Format(dateTimeLayout))
+	}
+	return "<" + string(obj.Type()) + ">"
+}
+
+// match returns true if obj is the result described by want. Numbers match to
+// within a rounding error, and dates and times match their formatted strings.
+func match(want json.RawMessage, obj object.Object) bool {
+	var w any
+	if err := json.Unmarshal(want, &w); err != nil {
+		return false
+	}
+	switch w := w.(type) {
+	case nil:
+		_, ok := obj.(object.Null)
+		return ok
+	case float64:
+		n, ok := obj.(object.Number)
+		return ok && math.Abs(n.Val-w) < 1e-9
+	case bool:
+		b, ok := obj.(object.Boolean)
+		return ok && b.Val == w
+	case string:
+		return format(obj) == strconv.Quote(w)
+	}
+	return false
+}
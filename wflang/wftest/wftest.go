@@ -0,0 +1,208 @@
+// Package wftest runs golden-result tests of formulas. A test file (.wftest) pairs
+// a formula with fixture data, and lists the results the formula is expected to
+// return on given days. See docs/wftest.md for the file format.
+package wftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/ast"
+	"github.com/scatternoodle/wflang/wflang/eval"
+	"github.com/scatternoodle/wflang/wflang/fixture"
+	"github.com/scatternoodle/wflang/wflang/lexer"
+	"github.com/scatternoodle/wflang/wflang/parser"
+)
+
+// Ext is the file extension of test files.
+const Ext = ".wftest"
+
+// Suite is a test file. Relative paths in it are relative to the directory of the
+// file.
+type Suite struct {
+	Path    string `json:"-"`
+	Formula string `json:"formula"` // path to the formula under test.
+	Source  string `json:"source"`  // the formula itself, if Formula is empty.
+	Fixture string `json:"fixture"` // path to the default fixture of the cases.
+	Cases   []Case `json:"cases"`
+}
+
+// Case is a single expectation of a Suite.
+type Case struct {
+	Name      string          `json:"name"`      // defaults to the day.
+	Day       string          `json:"day"`       // yyyy-MM-dd.
+	Fixture   string          `json:"fixture"`   // overrides the fixture of the suite.
+	Want      json.RawMessage `json:"want"`      // the expected result.
+	WantError string          `json:"wantError"` // if set, evaluation must fail with an error containing it.
+}
+
+// Result is the outcome of a Case.
+type Result struct {
+	Suite string // path of the test file.
+	Name  string
+	Pass  bool
+	Want  string // the expected result, as it would be printed.
+	Have  string // the actual result, or the error raised.
+}
+
+// Load reads the test file at path.
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Suite{Path: path}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch {
+	case s.Formula == "" && s.Source == "":
+		return nil, fmt.Errorf("%s: one of formula or source is required", path)
+	case s.Formula != "" && s.Source != "":
+		return nil, fmt.Errorf("%s: formula and source are mutually exclusive", path)
+	case len(s.Cases) == 0:
+		return nil, fmt.Errorf("%s: no cases", path)
+	}
+	for i, c := range s.Cases {
+		if _, err := time.Parse("2006-01-02", c.Day); err != nil {
+			return nil, fmt.Errorf("%s: cases[%d].day: invalid date %q, want yyyy-MM-dd", path, i, c.Day)
+		}
+		if c.Fixture == "" && s.Fixture == "" {
+			return nil, fmt.Errorf("%s: cases[%d]: no fixture", path, i)
+		}
+		if c.WantError == "" && len(c.Want) == 0 {
+			return nil, fmt.Errorf("%s: cases[%d]: one of want or wantError is required", path, i)
+		}
+		if s.Cases[i].Name == "" {
+			s.Cases[i].Name = c.Day
+		}
+	}
+	return s, nil
+}
+
+// Find returns the test files at paths. Directories are searched recursively.
+func Find(paths ...string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (p == path || filepath.Ext(p) == Ext) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Run evaluates every case of the suite. Errors that prevent the suite from
+// running at all, such as a formula that does not parse, fail each of its cases.
+func (s *Suite) Run() []Result {
+	root, err := s.parse()
+	fixtures := map[string]*fixture.Fixture{}
+
+	results := make([]Result, len(s.Cases))
+	for i, c := range s.Cases {
+		res := Result{Suite: s.Path, Name: c.Name, Want: c.want()}
+		if err != nil {
+			res.Have = err.Error()
+		} else {
+			res.Have, res.Pass = s.runCase(root, c, fixtures)
+		}
+		results[i] = res
+	}
+	return results
+}
+
+func (s *Suite) runCase(root *ast.AST, c Case, fixtures map[string]*fixture.Fixture) (have string, pass bool) {
+	path := s.resolve(c.Fixture)
+	if c.Fixture == "" {
+		path = s.resolve(s.Fixture)
+	}
+	fix, ok := fixtures[path]
+	if !ok {
+		var err error
+		if fix, err = fixture.Load(path); err != nil {
+			return err.Error(), false
+		}
+		fixtures[path] = fix
+	}
+
+	day, _ := time.Parse("2006-01-02", c.Day)
+	ctx, err := fix.Context(day)
+	if err != nil {
+		return err.Error(), false
+	}
+	obj, err := eval.Eval(root, ctx)
+	if err != nil {
+		return "error: " + err.Error(), c.WantError != "" && strings.Contains(err.Error(), c.WantError)
+	}
+	if c.WantError != "" {
+		return format(obj), false
+	}
+	return format(obj), match(c.Want, obj)
+}
+
+// parse parses the formula under test. Type errors are left to surface at
+// evaluation, as they may not affect the cases.
+func (s *Suite) parse() (*ast.AST, error) {
+	src := s.Source
+	if s.Formula != "" {
+		data, err := os.ReadFile(s.resolve(s.Formula))
+		if err != nil {
+			return nil, err
+		}
+		src = string(data)
+	}
+
+	prs := parser.New(lexer.New(src))
+	root, err := prs.AST()
+	if err != nil {
+		return nil, err
+	}
+	if errs := prs.Errors(); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+			var perr parser.ParseErr
+			if errors.As(e, &perr) {
+				start, _ := perr.Pos()
+				msgs[i] = fmt.Sprintf("%d:%d: %s", start.Line+1, start.Col+1, perr.Msg)
+			}
+		}
+		return nil, errors.New("parse errors: " + strings.Join(msgs, "; "))
+	}
+	return root, nil
+}
+
+func (s *Suite) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(s.Path), path)
+}
+
+func (c Case) want() string {
+	if c.WantError != "" {
+		return "error containing " + fmt.Sprintf("%q", c.WantError)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, c.Want); err != nil {
+		return string(c.Want)
+	}
+	return buf.String()
+}
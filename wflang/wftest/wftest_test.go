@@ -0,0 +1,120 @@
+package wftest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scatternoodle/wflang/wflang/object"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		file string
+		want []Result
+	}{
+		{"regular_hours.wftest", []Result{
+			{Name: "partial shift", Pass: true, Want: "7.5", Have: "7.5"},
+			{Name: "2024-03-05", Pass: true, Want: "8", Have: "8"},
+			{Name: "no time", Pass: true, Want: "0", Have: "0"},
+		}},
+		{"failing.wftest", []Result{
+			{Name: "wrong value", Want: `"OT"`, Have: `"REG"`},
+			{Name: "unexpected error", Want: `"REG"`, Have: "error: 1:37: division by zero"},
+			{Name: "expected error", Pass: true, Want: `error containing "division by zero"`, Have: "error: 1:37: division by zero"},
+			{Name: "outside calendar", Want: `"REG"`, Have: "2024-04-01 is not in any pay period of the fixture"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.file)
+			suite, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			have := suite.Run()
+			if len(have) != len(tt.want) {
+				t.Fatalf("results: have %d, want %d", len(have), len(tt.want))
+			}
+			for i, want := range tt.want {
+				want.Suite = path
+				if have[i] != want {
+					t.Errorf("result %d:\nhave %+v\nwant %+v", i, have[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []string{
+		`{"fixture": "f.json", "cases": [{"day": "2024-01-01", "want": 1}]}`,
+		`{"source": "1", "formula": "a.wf", "fixture": "f.json", "cases": [{"day": "2024-01-01", "want": 1}]}`,
+		`{"source": "1", "fixture": "f.json"}`,
+		`{"source": "1", "fixture": "f.json", "cases": [{"day": "01/01/2024", "want": 1}]}`,
+		`{"source": "1", "cases": [{"day": "2024-01-01", "want": 1}]}`,
+		`{"source": "1", "fixture": "f.json", "cases": [{"day": "2024-01-01"}]}`,
+		`{"source": "1", "fixture": "f.json", "case": [{"day": "2024-01-01", "want": 1}]}`,
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.wftest")
+			if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	suite := &Suite{Path: "testdata/x.wftest", Source: "1 +", Fixture: "employee.json", Cases: []Case{{Name: "a", Day: "2024-03-04", Want: []byte("1")}}}
+	res := suite.Run()
+	if len(res) != 1 || res[0].Pass {
+		t.Fatalf("have %+v, want one failure", res)
+	}
+	if want := "parse errors: 1:3: "; !strings.Contains(res[0].Have, want) {
+		t.Errorf("have %q, want the 1-based position of the error %q", res[0].Have, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	have, err := Find("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 2 {
+		t.Errorf("have %v, want the 2 test files", have)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		want string
+		obj  object.Object
+		ok   bool
+	}{
+		{`null`, object.Null{}, true},
+		{`null`, object.Number{Val: 0, Static: true}, false},
+		{`7.5`, object.Number{Val: 7.5, Static: true}, true},
+		{`0.3`, object.Number{Val: 0.1 + 0.2, Static: true}, true},
+		{`"7.5"`, object.Number{Val: 7.5, Static: true}, false},
+		{`true`, object.Boolean{Val: true, Static: true}, true},
+		{`"REG"`, object.String{Val: "REG", Static: true}, true},
+		{`"2024-03-04"`, object.Date{Val: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Static: true}, true},
+		{`"2024-03-04"`, object.String{Val: "2024-03-05", Static: true}, false},
+		{`[1]`, object.Number{Val: 1, Static: true}, false},
+	}
+
+	for _, tt := range tests {
+		if have := match([]byte(tt.want), tt.obj); have != tt.ok {
+			t.Errorf("match(%s, %s): have %t, want %t", tt.want, format(tt.obj), have, tt.ok)
+		}
+	}
+}